@@ -0,0 +1,79 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// drainDeactivatePadding is the time left on the context after draining, to deactivate the appliance.
+const drainDeactivatePadding = time.Minute
+
+// drainAppliance stops new sessions on the gateway, by suspending it, or on Controllers older then 6.1
+// by setting the vpn weight to 0, and waits until the active sessions reported in the appliance stats
+// drop to threshold or below. The wait is capped at the deadline of ctx, minus drainDeactivatePadding.
+// If timeout or the capped deadline expires first, drainAppliance returns false without an error.
+func drainAppliance(ctx context.Context, meta interface{}, appliance *openapi.Appliance, threshold int, timeout time.Duration) (bool, error) {
+	gateway := appliance.GetGateway()
+	if !gateway.GetEnabled() {
+		return true, nil
+	}
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return false, err
+	}
+	api := meta.(*Client).API.AppliancesApi
+	currentVersion := meta.(*Client).ApplianceVersion
+	if currentVersion.GreaterThanOrEqual(Appliance61Version) {
+		gateway.SetSuspended(true)
+	} else {
+		vpn := gateway.GetVpn()
+		vpn.SetWeight(0)
+		gateway.SetVpn(vpn)
+	}
+	appliance.SetGateway(gateway)
+	log.Printf("[DEBUG] Draining gateway %s", appliance.GetName())
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	if _, _, err := api.AppliancesIdPut(ctx, appliance.GetId()).Appliance(*appliance).Execute(); err != nil {
+		return false, fmt.Errorf("Failed to drain gateway %s, %w", appliance.GetName(), prettyPrintAPIError(err))
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline) - drainDeactivatePadding; left < timeout {
+			timeout = left
+		}
+	}
+	drainCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	b := backoff.NewExponentialBackOff()
+	b.MaxInterval = 30 * time.Second
+	b.MaxElapsedTime = timeout
+	err = backoff.Retry(func() error {
+		status, err := getApplianceStatus(drainCtx, meta, appliance.GetId())
+		if err != nil {
+			return err
+		}
+		sessions := int(status.GetNumberOfSessions())
+		log.Printf("[DEBUG] Gateway %s has %d active sessions, want %d or less", appliance.GetName(), sessions, threshold)
+		if sessions <= threshold {
+			return nil
+		}
+		return errDrainSessions
+	}, backoff.WithContext(b, drainCtx))
+	if err != nil {
+		// the drain timed out, ctx still has time left to deactivate the appliance.
+		if errors.Is(err, errDrainSessions) || (drainCtx.Err() != nil && ctx.Err() == nil) {
+			log.Printf("[WARN] Gateway %s was not drained within %s", appliance.GetName(), timeout)
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+var errDrainSessions = errors.New("gateway has active sessions")
@@ -0,0 +1,108 @@
+package appgate
+
+import (
+	"bytes"
+	"compress/gzip"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	seedFormatJSON       = "json"
+	seedFormatBase64     = "base64"
+	seedFormatCloudInit  = "cloud-init"
+	seedFormatIgnition   = "ignition"
+	seedFormatGzipBase64 = "gzip+base64"
+
+	defaultSeedPath = "/home/cz/seed.json"
+	// seedFileMode is the permission of the seed file written by cloud-init and ignition, 0600.
+	seedFileMode = 0600
+	// ignitionVersion is the ignition config spec version rendered by the ignition output format.
+	ignitionVersion = "3.3.0"
+)
+
+func seedFormats() []string {
+	return []string{
+		seedFormatJSON,
+		seedFormatBase64,
+		seedFormatCloudInit,
+		seedFormatIgnition,
+		seedFormatGzipBase64,
+	}
+}
+
+// renderApplianceSeed renders the JSON seed exported from the Controller in one of the seedFormats.
+// path is where cloud-init and ignition write the seed file on the appliance.
+func renderApplianceSeed(seed []byte, format, path string) (string, error) {
+	switch format {
+	case seedFormatJSON:
+		return string(seed), nil
+	case seedFormatBase64:
+		return b64.StdEncoding.EncodeToString(seed), nil
+	case seedFormatCloudInit:
+		return renderSeedCloudInit(seed, path)
+	case seedFormatIgnition:
+		return renderSeedIgnition(seed, path)
+	case seedFormatGzipBase64:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(seed); err != nil {
+			return "", fmt.Errorf("Could not compress seed file: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return "", fmt.Errorf("Could not compress seed file: %w", err)
+		}
+		return b64.StdEncoding.EncodeToString(buf.Bytes()), nil
+	}
+	return "", fmt.Errorf("unsupported seed output_format %q, expected one of %s", format, strings.Join(seedFormats(), ", "))
+}
+
+// renderSeedCloudInit renders a #cloud-config document with a write_files entry for the seed.
+// JSON strings are valid YAML double-quoted scalars, so the path is quoted with json.Marshal.
+func renderSeedCloudInit(seed []byte, path string) (string, error) {
+	quotedPath, err := json.Marshal(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+	b.WriteString("write_files:\n")
+	fmt.Fprintf(&b, "  - path: %s\n", quotedPath)
+	fmt.Fprintf(&b, "    permissions: \"%04o\"\n", seedFileMode)
+	b.WriteString("    encoding: b64\n")
+	fmt.Fprintf(&b, "    content: %s\n", b64.StdEncoding.EncodeToString(seed))
+	return b.String(), nil
+}
+
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+	Storage struct {
+		Files []ignitionFile `json:"files"`
+	} `json:"storage"`
+}
+
+type ignitionFile struct {
+	Path     string `json:"path"`
+	Mode     int    `json:"mode"`
+	Contents struct {
+		Source string `json:"source"`
+	} `json:"contents"`
+}
+
+// renderSeedIgnition renders an ignition config that writes the seed as a data URL.
+func renderSeedIgnition(seed []byte, path string) (string, error) {
+	file := ignitionFile{Path: path, Mode: seedFileMode}
+	file.Contents.Source = "data:;base64," + b64.StdEncoding.EncodeToString(seed)
+	config := ignitionConfig{}
+	config.Ignition.Version = ignitionVersion
+	config.Storage.Files = []ignitionFile{file}
+	out, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("Could not render ignition config: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,71 @@
+package appgate
+
+import (
+	"bytes"
+	"compress/gzip"
+	b64 "encoding/base64"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in test-fixtures")
+
+// testSeed is a trimmed down seed file, as exported by AppliancesIdExportPost.
+var testSeed = []byte(`{"apiKey":"ZXhhbXBsZSBhcGkga2V5","controllers":["controller.devops"],"password":"cz","version":17}`)
+
+func TestRenderApplianceSeed(t *testing.T) {
+	// gzip output depends on the compress/flate implementation, so it is tested by decompressing.
+	for _, format := range []string{seedFormatJSON, seedFormatBase64, seedFormatCloudInit, seedFormatIgnition} {
+		t.Run(format, func(t *testing.T) {
+			got, err := renderApplianceSeed(testSeed, format, defaultSeedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("test-fixtures", "appliance_seed", format+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Fatalf("renderApplianceSeed(%s) =\n%s\nwant\n%s", format, got, want)
+			}
+		})
+	}
+
+	t.Run(seedFormatGzipBase64, func(t *testing.T) {
+		got, err := renderApplianceSeed(testSeed, seedFormatGzipBase64, defaultSeedPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressed, err := b64.StdEncoding.DecodeString(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		seed, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(seed, testSeed) {
+			t.Fatalf("decompressed seed = %s, want %s", seed, testSeed)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := renderApplianceSeed(testSeed, "yaml", defaultSeedPath); err == nil || !strings.Contains(err.Error(), "unsupported") {
+			t.Fatalf("expected unsupported format error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Upgrade statuses reported by GET /appliances/{id}/upgrade.
+const (
+	UpgradeStatusIdle        = "idle"
+	UpgradeStatusStarted     = "started"
+	UpgradeStatusDownloading = "downloading"
+	UpgradeStatusVerifying   = "verifying"
+	UpgradeStatusReady       = "ready"
+	UpgradeStatusInstalling  = "installing"
+	UpgradeStatusSuccess     = "success"
+	UpgradeStatusFailed      = "failed"
+)
+
+// getApplianceStatus returns the appliance from /stats/appliance, which includes the state and version.
+func getApplianceStatus(ctx context.Context, meta interface{}, applianceID string) (*openapi.ApplianceWithStatus, error) {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API.AppliancesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	stats, _, err := api.AppliancesStatusGet(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get appliance status, %w", prettyPrintAPIError(err))
+	}
+	for _, data := range stats.GetData() {
+		if data.GetId() == applianceID {
+			return &data, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find appliance %q in stats list", applianceID)
+}
+
+// applianceReadyState returns the state the appliance reports when it is healthy,
+// controller_ready for controllers and appliance_ready for other appliances.
+func applianceReadyState(appliance *openapi.Appliance) string {
+	ctrl := appliance.GetController()
+	if ctrl.GetEnabled() {
+		return ApplianceStateControllerReady
+	}
+	return ApplianceStateApplianceReady
+}
+
+// prepareApplianceUpgrade starts downloading and verifying the upgrade image on the appliance
+// and waits until the image is staged on the inactive partition.
+func prepareApplianceUpgrade(ctx context.Context, meta interface{}, applianceID, imageURL string, b *backoff.ExponentialBackOff) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.ApplianceUpgradeApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	prepare := openapi.NewAppliancesIdUpgradePreparePostRequest(imageURL)
+	if _, _, err := api.AppliancesIdUpgradePreparePost(ctx, applianceID).AppliancesIdUpgradePreparePostRequest(*prepare).Execute(); err != nil {
+		return fmt.Errorf("Failed to prepare upgrade on appliance %s, %w", applianceID, prettyPrintAPIError(err))
+	}
+	return waitForApplianceUpgradeStatus(ctx, meta, applianceID, UpgradeStatusReady, b)
+}
+
+// completeApplianceUpgrade installs the staged upgrade image, and waits for the appliance to come back in state.
+func completeApplianceUpgrade(ctx context.Context, meta interface{}, applianceID, state string, switchPartition bool, b *backoff.ExponentialBackOff) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.ApplianceUpgradeApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	complete := openapi.NewAppliancesIdUpgradeCompletePostRequest()
+	complete.SetSwitchPartition(switchPartition)
+	if _, _, err := api.AppliancesIdUpgradeCompletePost(ctx, applianceID).AppliancesIdUpgradeCompletePostRequest(*complete).Execute(); err != nil {
+		return fmt.Errorf("Failed to complete upgrade on appliance %s, %w", applianceID, prettyPrintAPIError(err))
+	}
+	if !switchPartition {
+		// the new version is installed on the inactive partition, and used after the next reboot.
+		return waitForApplianceUpgradeStatus(ctx, meta, applianceID, UpgradeStatusSuccess, b)
+	}
+	// the appliance is in upgrading state while it reboots into the new partition.
+	if err := waitForApplianceState(ctx, meta, applianceID, ApplianceStateUpgrading, upgradingBackOff()); err != nil {
+		log.Printf("[DEBUG] Did not observe appliance %s in %s state: %s", applianceID, ApplianceStateUpgrading, err)
+	}
+	return waitForApplianceState(ctx, meta, applianceID, state, b)
+}
+
+// cancelApplianceUpgrade removes a prepared upgrade image from the appliance.
+func cancelApplianceUpgrade(ctx context.Context, meta interface{}, applianceID string) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.ApplianceUpgradeApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	if _, _, err := api.AppliancesIdUpgradeDelete(ctx, applianceID).Execute(); err != nil {
+		return fmt.Errorf("Failed to cancel upgrade on appliance %s, %w", applianceID, prettyPrintAPIError(err))
+	}
+	return nil
+}
+
+// getApplianceUpgradeStatus returns the status of the upgrade on the appliance, and details on failure.
+func getApplianceUpgradeStatus(ctx context.Context, meta interface{}, applianceID string) (string, string, error) {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return "", "", err
+	}
+	api := meta.(*Client).API.ApplianceUpgradeApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	upgrade, _, err := api.AppliancesIdUpgradeGet(ctx, applianceID).Execute()
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to get upgrade status on appliance %s, %w", applianceID, prettyPrintAPIError(err))
+	}
+	return upgrade.GetStatus(), upgrade.GetDetails(), nil
+}
+
+// waitForApplianceUpgradeStatus is a blocking function that does exponential backOff on the appliance upgrade status
+// until it reaches status, or the upgrade fails.
+func waitForApplianceUpgradeStatus(ctx context.Context, meta interface{}, applianceID, status string, b *backoff.ExponentialBackOff) error {
+	return backoff.Retry(func() error {
+		got, details, err := getApplianceUpgradeStatus(ctx, meta, applianceID)
+		if err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] Appliance %s upgrade status is %s want status %s", applianceID, got, status)
+		if got == status {
+			return nil
+		}
+		if got == UpgradeStatusFailed {
+			return backoff.Permanent(fmt.Errorf("upgrade failed on appliance %q: %s", applianceID, details))
+		}
+		return fmt.Errorf("appliance %q upgrade status is %s expected %s", applianceID, got, status)
+	}, b)
+}
+
+// upgradeAppliance prepares and completes an upgrade on the appliance, and waits until it is healthy.
+// The upgrade is skipped if the appliance already runs version, which returns true. If version is empty,
+// the version in the file name of the image is used, so a rerun skips the appliances that are already upgraded.
+// An *ApplianceUpgradeIncompleteError is returned if the image is installed, but the appliance is not healthy.
+func upgradeAppliance(ctx context.Context, meta interface{}, appliance *openapi.Appliance, imageURL, version string, switchPartition bool, timeout time.Duration) (bool, error) {
+	id := appliance.GetId()
+	if !appliance.GetActivated() {
+		return false, fmt.Errorf("Can not upgrade the inactive appliance %q, the appliance need to be seeded first.", appliance.GetName())
+	}
+	if len(version) == 0 {
+		version = upgradeImageVersion(imageURL)
+	}
+	status, err := getApplianceStatus(ctx, meta, id)
+	if err != nil {
+		return false, err
+	}
+	if applianceRunsVersion(status.GetVersion(), version) {
+		log.Printf("[DEBUG] Appliance %s already runs %s, skip upgrade", appliance.GetName(), status.GetVersion())
+		return true, nil
+	}
+	// each step has its own budget within timeout, prepare can use at most half of it, and complete
+	// the time that is left after prepare.
+	start := time.Now()
+	if err := prepareApplianceUpgrade(ctx, meta, id, imageURL, upgradeStepBackOff(timeout/2)); err != nil {
+		return false, err
+	}
+	log.Printf("[DEBUG] Upgrade image staged on appliance %s, completing upgrade", appliance.GetName())
+	if err := completeApplianceUpgrade(ctx, meta, id, applianceReadyState(appliance), switchPartition, upgradeStepBackOff(timeout-time.Since(start))); err != nil {
+		return false, &ApplianceUpgradeIncompleteError{Name: appliance.GetName(), err: err}
+	}
+	return false, nil
+}
+
+// upgradeStepBackOff returns the backoff for one step of the upgrade, which stops after budget.
+func upgradeStepBackOff(budget time.Duration) *backoff.ExponentialBackOff {
+	b := backoff.NewExponentialBackOff()
+	b.MaxInterval = 30 * time.Second
+	// a MaxElapsedTime of 0 never stops.
+	if budget <= 0 {
+		budget = time.Nanosecond
+	}
+	b.MaxElapsedTime = budget
+	return b
+}
+
+// ApplianceUpgradeIncompleteError is returned when the upgrade image was installed,
+// but the appliance never reached a healthy state after the upgrade.
+type ApplianceUpgradeIncompleteError struct {
+	Name string
+	err  error
+}
+
+func (e *ApplianceUpgradeIncompleteError) Error() string {
+	return fmt.Sprintf("Appliance %s never reached a healthy state after the upgrade: %s", e.Name, e.err)
+}
+
+func (e *ApplianceUpgradeIncompleteError) Unwrap() error {
+	return e.err
+}
+
+// upgradingBackOff is used to observe the short lived upgrading state after an upgrade is completed.
+func upgradingBackOff() *backoff.ExponentialBackOff {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Minute
+	return b
+}
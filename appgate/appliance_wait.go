@@ -0,0 +1,144 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func waitForStateSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Wait for the appliance to reach a state after create and update.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"state": {
+					Type:        schema.TypeString,
+					Description: "State to wait for, defaults to controller_ready for controllers and appliance_ready for other appliances.",
+					Optional:    true,
+					ValidateFunc: validation.StringInSlice([]string{
+						ApplianceStateApplianceReady,
+						ApplianceStateControllerReady,
+						ApplianceStateApplianceRegistering,
+						ApplianceStateApplianceActivating,
+						ApplianceStateWaitingConfig,
+					}, false),
+				},
+				"functions": {
+					Type:        schema.TypeSet,
+					Description: "Functions that must be enabled and healthy.",
+					Optional:    true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(append(siteUpgradeFunctions(), applianceFunctionController), false),
+					},
+				},
+				"timeout": {
+					Type:         schema.TypeString,
+					Description:  "Maximum amount of time to wait.",
+					Optional:     true,
+					Default:      "20m",
+					ValidateFunc: validateDuration,
+				},
+			},
+		},
+	}
+}
+
+// waitForApplianceFromConfig blocks until the appliance reaches the state and function
+// readiness configured in the wait_for_state block. Inactive appliances are not waited for,
+// since they only change state once they are seeded, and the seed depends on the appliance.
+func waitForApplianceFromConfig(ctx context.Context, d *schema.ResourceData, meta interface{}, appliance *openapi.Appliance) error {
+	v, ok := d.GetOk("wait_for_state")
+	if !ok {
+		return nil
+	}
+	raw := v.([]interface{})[0].(map[string]interface{})
+	if !appliance.GetActivated() {
+		log.Printf("[DEBUG] Appliance %s is not activated, skip wait_for_state", appliance.GetName())
+		return nil
+	}
+	state := applianceReadyState(appliance)
+	if v, ok := raw["state"].(string); ok && len(v) > 0 {
+		state = v
+	}
+	timeout, err := time.ParseDuration(raw["timeout"].(string))
+	if err != nil {
+		return err
+	}
+	var functions []string
+	if v, ok := raw["functions"].(*schema.Set); ok {
+		functions, _ = readArrayOfStringsFromConfig(v.List())
+	}
+
+	start := time.Now()
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = timeout
+	if err := waitForApplianceState(ctx, meta, appliance.GetId(), state, b); err != nil {
+		return fmt.Errorf("Appliance %s never reached %s within %s: %w", appliance.GetName(), state, timeout, err)
+	}
+	if len(functions) == 0 {
+		return nil
+	}
+	b = backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = timeout - time.Since(start)
+	if err := waitForApplianceFunctions(ctx, meta, appliance.GetId(), functions, b); err != nil {
+		return fmt.Errorf("Appliance %s functions never became healthy within %s: %w", appliance.GetName(), timeout, err)
+	}
+	return nil
+}
+
+// waitForApplianceFunctions is a blocking function that does exponential backOff on appliance stats
+// until functions are enabled on the appliance, and the appliance is online and healthy.
+func waitForApplianceFunctions(ctx context.Context, meta interface{}, applianceID string, functions []string, b *backoff.ExponentialBackOff) error {
+	return backoff.Retry(func() error {
+		status, err := getApplianceStatus(ctx, meta, applianceID)
+		if err != nil {
+			return err
+		}
+		enabled := applianceStatusFunctions(*status)
+		missing := make([]string, 0)
+		for _, f := range functions {
+			if !inArray(f, enabled) {
+				missing = append(missing, f)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("appliance %q does not have %s enabled", applianceID, strings.Join(missing, ", "))
+		}
+		log.Printf("[DEBUG] Appliance %s status is %s online %t", applianceID, status.GetStatus(), status.GetOnline())
+		if !status.GetOnline() || status.GetStatus() != ApplianceStatusHealthy {
+			return fmt.Errorf("appliance %q is %s: %s", applianceID, status.GetStatus(), status.GetDetails())
+		}
+		return nil
+	}, b)
+}
+
+// waitForApplianceRestart waits for the appliance to go offline after a reboot, and then waits
+// until it is back in state. The appliance may restart before it is observed offline, so failing
+// to observe it offline is only logged.
+func waitForApplianceRestart(ctx context.Context, meta interface{}, applianceID, state string, b *backoff.ExponentialBackOff) error {
+	err := backoff.Retry(func() error {
+		status, err := getApplianceStatus(ctx, meta, applianceID)
+		if err != nil {
+			return err
+		}
+		if !status.GetOnline() || status.GetState() != state {
+			return nil
+		}
+		return fmt.Errorf("appliance %q is still online in state %s", applianceID, status.GetState())
+	}, upgradingBackOff())
+	if err != nil {
+		log.Printf("[DEBUG] Did not observe appliance %s restart: %s", applianceID, err)
+	}
+	return waitForApplianceState(ctx, meta, applianceID, state, b)
+}
@@ -0,0 +1,159 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// updatedSchema is the timestamp of the last update of the object on the Controller.
+// The plugin SDK does not expose private state to the CRUD functions, so it is stored
+// as a computed attribute and used by checkConcurrentUpdate.
+func updatedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Last update time of the object on the Controller.",
+		Computed:    true,
+	}
+}
+
+// updatedCustomizeDiff marks updated as unknown in the plan when any other attribute changes,
+// since the update changes it on the Controller.
+func updatedCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if len(d.Id()) == 0 {
+		return nil
+	}
+	for _, key := range d.GetChangedKeysPrefix("") {
+		if key != "updated" {
+			return d.SetNewComputed("updated")
+		}
+	}
+	return nil
+}
+
+// withUpdatedCustomizeDiff adds updatedCustomizeDiff to the resources with the updated attribute.
+func withUpdatedCustomizeDiff(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range resources {
+		if s, ok := r.Schema["updated"]; !ok || !s.Computed {
+			continue
+		}
+		if r.CustomizeDiff == nil {
+			r.CustomizeDiff = updatedCustomizeDiff
+			continue
+		}
+		r.CustomizeDiff = customdiff.All(r.CustomizeDiff, updatedCustomizeDiff)
+	}
+	return resources
+}
+
+func setUpdated(d *schema.ResourceData, updated time.Time) {
+	if updated.IsZero() {
+		return
+	}
+	d.Set("updated", updated.UTC().Format(time.RFC3339Nano))
+}
+
+// updatedFromMap returns the updated timestamp of objects that are not decoded into a model,
+// such as client profiles.
+func updatedFromMap(m map[string]interface{}) time.Time {
+	s, _ := m["updated"].(string)
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// ConcurrentUpdateError is returned when the object was changed on the Controller
+// after the plan was made, and the change overlaps with the planned changes.
+type ConcurrentUpdateError struct {
+	ID        string
+	Updated   time.Time
+	Conflicts []string
+}
+
+func (e *ConcurrentUpdateError) Error() string {
+	return fmt.Sprintf(
+		"%s was modified on the Controller at %s, after the plan was made. "+
+			"The following attributes were changed both on the Controller and in the configuration, "+
+			"run terraform plan again to review the changes:\n%s",
+		e.ID, e.Updated.Format(time.RFC3339), strings.Join(e.Conflicts, "\n"),
+	)
+}
+
+// checkConcurrentUpdate is used in the Update functions, after the object is read from the Controller
+// and before the changes are written back. If the object has been updated since it was last read into
+// the state, the remote object is read again with res and compared with the state, attribute by attribute.
+// If the configuration changes attributes which were also changed on the Controller, a ConcurrentUpdateError
+// is returned, otherwise the update continues, since only the changed attributes are written to the remote object.
+func checkConcurrentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, res *schema.Resource, remoteUpdated time.Time) error {
+	v, ok := d.GetOk("updated")
+	if !ok || remoteUpdated.IsZero() {
+		return nil
+	}
+	stateUpdated, err := time.Parse(time.RFC3339Nano, v.(string))
+	if err != nil || stateUpdated.Equal(remoteUpdated) {
+		return nil
+	}
+	log.Printf("[DEBUG] %s was updated on the Controller at %s, state has %s", d.Id(), remoteUpdated, stateUpdated)
+
+	// read the remote object into a copy of the prior state, so we can compare each attribute.
+	remote := res.Data(&terraform.InstanceState{ID: d.Id()})
+	for key := range res.Schema {
+		old, _ := d.GetChange(key)
+		remote.Set(key, old)
+	}
+	if res.ReadContext != nil {
+		if diags := res.ReadContext(ctx, remote, meta); diags.HasError() {
+			return fmt.Errorf("Failed to read %s while checking for concurrent updates", d.Id())
+		}
+	} else if res.Read != nil {
+		if err := res.Read(remote, meta); err != nil {
+			return fmt.Errorf("Failed to read %s while checking for concurrent updates, %w", d.Id(), err)
+		}
+	}
+
+	conflicts := make([]string, 0)
+	for key := range res.Schema {
+		if key == "updated" || !d.HasChange(key) {
+			continue
+		}
+		old, new := d.GetChange(key)
+		current := remote.Get(key)
+		if attributeEqual(old, current) {
+			continue
+		}
+		if res.Schema[key].Sensitive {
+			conflicts = append(conflicts, fmt.Sprintf("  %s: (sensitive value)", key))
+			continue
+		}
+		conflicts = append(conflicts, fmt.Sprintf("  %s:\n    state:         %s\n    controller:    %s\n    configuration: %s", key, attributeString(old), attributeString(current), attributeString(new)))
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return &ConcurrentUpdateError{ID: d.Id(), Updated: remoteUpdated, Conflicts: conflicts}
+	}
+	log.Printf("[DEBUG] %s changes on the Controller does not overlap with the configuration, continue update", d.Id())
+	return nil
+}
+
+func attributeEqual(a, b interface{}) bool {
+	if s, ok := a.(*schema.Set); ok {
+		if o, ok := b.(*schema.Set); ok {
+			return s.Equal(o)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func attributeString(v interface{}) string {
+	if s, ok := v.(*schema.Set); ok {
+		return fmt.Sprintf("%v", s.List())
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,153 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCheckConcurrentUpdate(t *testing.T) {
+	stateUpdated := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	remoteUpdated := stateUpdated.Add(time.Minute)
+	remote := map[string]interface{}{
+		"name":  "web",
+		"notes": "changed in the admin UI",
+	}
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":    {Type: schema.TypeString, Required: true},
+			"notes":   {Type: schema.TypeString, Optional: true},
+			"updated": updatedSchema(),
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			for k, v := range remote {
+				d.Set(k, v)
+			}
+			return nil
+		},
+	}
+	state := &terraform.InstanceState{
+		ID: "7f5fe1c4-5b5c-4e4b-8a69-1a7b1ae3b5c3",
+		Attributes: map[string]string{
+			"id":      "7f5fe1c4-5b5c-4e4b-8a69-1a7b1ae3b5c3",
+			"name":    "web",
+			"notes":   "managed by terraform",
+			"updated": stateUpdated.Format(time.RFC3339Nano),
+		},
+	}
+	data := func(t *testing.T, config map[string]interface{}) *schema.ResourceData {
+		sm := schema.InternalMap(res.Schema)
+		diff, err := sm.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := sm.Data(state, diff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		updated   time.Time
+		conflicts []string
+	}{
+		{
+			name:    "not modified",
+			config:  map[string]interface{}{"name": "web", "notes": "new notes"},
+			updated: stateUpdated,
+		},
+		{
+			name:    "modified without overlap",
+			config:  map[string]interface{}{"name": "api", "notes": "managed by terraform"},
+			updated: remoteUpdated,
+		},
+		{
+			name:      "modified with overlap",
+			config:    map[string]interface{}{"name": "web", "notes": "new notes"},
+			updated:   remoteUpdated,
+			conflicts: []string{"notes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkConcurrentUpdate(context.Background(), data(t, tt.config), nil, res, tt.updated)
+			if len(tt.conflicts) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			var conflict *ConcurrentUpdateError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("expected ConcurrentUpdateError, got %v", err)
+			}
+			if len(conflict.Conflicts) != len(tt.conflicts) {
+				t.Fatalf("expected %d conflicts, got %v", len(tt.conflicts), conflict.Conflicts)
+			}
+			for i, key := range tt.conflicts {
+				if !strings.HasPrefix(strings.TrimSpace(conflict.Conflicts[i]), key+":") {
+					t.Errorf("expected conflict on %s, got %s", key, conflict.Conflicts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdatedCustomizeDiff(t *testing.T) {
+	sm := schema.InternalMap(map[string]*schema.Schema{
+		"name":    {Type: schema.TypeString, Required: true},
+		"tags":    {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"updated": updatedSchema(),
+	})
+	state := &terraform.InstanceState{
+		ID: "7f5fe1c4-5b5c-4e4b-8a69-1a7b1ae3b5c3",
+		Attributes: map[string]string{
+			"id":      "7f5fe1c4-5b5c-4e4b-8a69-1a7b1ae3b5c3",
+			"name":    "web",
+			"tags.#":  "0",
+			"updated": "2021-03-01T10:00:00Z",
+		},
+	}
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		computed bool
+	}{
+		{"no changes", map[string]interface{}{"name": "web"}, false},
+		{"name changed", map[string]interface{}{"name": "api"}, true},
+		{"tag added", map[string]interface{}{"name": "web", "tags": []interface{}{"prod"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := sm.Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.config), updatedCustomizeDiff, nil, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var computed bool
+			if diff != nil {
+				if attr, ok := diff.Attributes["updated"]; ok {
+					computed = attr.NewComputed
+				}
+			}
+			if computed != tt.computed {
+				t.Errorf("updated computed = %t, want %t", computed, tt.computed)
+			}
+		})
+	}
+}
+
+func TestWithUpdatedCustomizeDiff(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if _, ok := r.Schema["updated"]; ok && r.CustomizeDiff == nil {
+			t.Errorf("%s has updated, but no CustomizeDiff", name)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package appgate
 
 import (
-	b64 "encoding/base64"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,6 +10,7 @@ import (
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceAppgateApplianceSeed() *schema.Resource {
@@ -44,9 +45,22 @@ func dataSourceAppgateApplianceSeed() *schema.Resource {
 				Sensitive:     true,
 				ConflictsWith: []string{"provide_cloud_ssh_key", "provide_cloud_ssh_key"},
 			},
+			"output_format": {
+				Type:         schema.TypeString,
+				Description:  "Format of seed_file.",
+				Optional:     true,
+				Default:      seedFormatBase64,
+				ValidateFunc: validation.StringInSlice(seedFormats(), false),
+			},
+			"seed_path": {
+				Type:        schema.TypeString,
+				Description: "Path of the seed file on the appliance, used by the cloud-init and ignition output formats.",
+				Optional:    true,
+				Default:     defaultSeedPath,
+			},
 			"seed_file": {
 				Type:        schema.TypeString,
-				Description: "Seed file (json) generated from appliance used in remote-exec.",
+				Description: "Seed file (json) generated from appliance used in remote-exec, rendered in output_format.",
 				Sensitive:   true,
 				Computed:    true,
 			},
@@ -87,35 +101,44 @@ func dataSourceAppgateApplianceSeedRead(d *schema.ResourceData, meta interface{}
 		return nil
 	}
 
-	exportRequest := api.AppliancesIdExportPost(ctx, appliance.GetId())
-	password, passwordOk := d.GetOk("password")
-	sshKey, sshOk := d.GetOk("ssh_key")
-	cloudKey, cloudOk := d.GetOk("provide_cloud_ssh_key")
-
 	sshConfig := openapi.NewSSHConfig()
-	if passwordOk {
+	if password, ok := d.GetOk("password"); ok {
 		sshConfig.Password = openapi.PtrString(password.(string))
 		d.Set("password", password.(string))
 	}
-	if sshOk {
+	if sshKey, ok := d.GetOk("ssh_key"); ok {
 		sshConfig.SshKey = openapi.PtrString(sshKey.(string))
 		d.Set("ssh_key", sshKey.(string))
 	}
-	if cloudOk {
+	if cloudKey, ok := d.GetOk("provide_cloud_ssh_key"); ok {
 		sshConfig.ProvideCloudSSHKey = openapi.PtrBool(cloudKey.(bool))
 		d.Set("provide_cloud_ssh_key", true)
 	}
-	exportRequest = exportRequest.SSHConfig(*sshConfig)
-	seedmap, _, err := exportRequest.Execute()
+	seed, err := exportApplianceSeed(ctx, api, appliance.GetId(), *sshConfig)
 	if err != nil {
-		return fmt.Errorf("Could not export appliance %w", prettyPrintAPIError(err))
+		return err
 	}
-	encodedSeed, err := json.Marshal(seedmap)
+
+	rendered, err := renderApplianceSeed(seed, d.Get("output_format").(string), d.Get("seed_path").(string))
 	if err != nil {
-		return fmt.Errorf("Could not parse json seed file: %w", err)
+		return err
 	}
-
-	d.Set("seed_file", b64.StdEncoding.EncodeToString([]byte(encodedSeed)))
+	d.Set("seed_file", rendered)
 
 	return nil
 }
+
+// exportApplianceSeed exports the seed file of an inactive appliance as JSON.
+// The seed contains the activation credentials of the appliance, so it should be kept
+// out of the state whenever possible, with the ephemeral appgatesdp_appliance_seed.
+func exportApplianceSeed(ctx context.Context, api *openapi.AppliancesApiService, applianceID string, sshConfig openapi.SSHConfig) ([]byte, error) {
+	seedmap, _, err := api.AppliancesIdExportPost(ctx, applianceID).SSHConfig(sshConfig).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("Could not export appliance %w", prettyPrintAPIError(err))
+	}
+	encodedSeed, err := json.Marshal(seedmap)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse json seed file: %w", err)
+	}
+	return encodedSeed, nil
+}
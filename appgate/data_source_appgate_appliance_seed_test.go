@@ -21,6 +21,7 @@ func TestAccAppgateApplianceSeedDataSource(t *testing.T) {
 					resource.TestCheckResourceAttrPair(dataSourceName, "appliance_id", resourceName, "id"),
 					resource.TestCheckResourceAttrSet("data.appgatesdp_appliance_seed.test_gateway_seed_file", "password"),
 					resource.TestCheckResourceAttrSet("data.appgatesdp_appliance_seed.test_gateway_seed_file", "seed_file"),
+					resource.TestCheckResourceAttr("data.appgatesdp_appliance_seed.test_gateway_seed_file", "output_format", "base64"),
 				),
 			},
 		},
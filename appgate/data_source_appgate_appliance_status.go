@@ -0,0 +1,271 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ApplianceStatusHealthy is the status reported by /appliances/status for a healthy appliance.
+const ApplianceStatusHealthy = "healthy"
+
+func dataSourceAppgateApplianceStatus() *schema.Resource {
+	s := applianceStatusSchema()
+	s["appliance_id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"appliance_name"},
+	}
+	s["appliance_name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"appliance_id"},
+	}
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateApplianceStatusRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceAppgateApplianceStatuses() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateApplianceStatusesRead,
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:         schema.TypeString,
+				Description:  "Only include appliances in this site.",
+				Optional:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"function": {
+				Type:         schema.TypeString,
+				Description:  "Only include appliances with this function enabled.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(append(siteUpgradeFunctions(), applianceFunctionController), false),
+			},
+			"healthy_count": {
+				Type:        schema.TypeInt,
+				Description: "Number of healthy appliances.",
+				Computed:    true,
+			},
+			"appliances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: func() map[string]*schema.Schema {
+						s := applianceStatusSchema()
+						s["appliance_id"] = &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						}
+						s["appliance_name"] = &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						}
+						return s
+					}(),
+				},
+			},
+		},
+	}
+}
+
+// applianceStatusSchema is the computed status attributes shared by the single and plural data source.
+func applianceStatusSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"site": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"online": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"healthy": {
+			Type:        schema.TypeBool,
+			Description: "True if the appliance is online, its status is healthy and it has at least one function enabled.",
+			Computed:    true,
+		},
+		"healthy_functions": {
+			Type:        schema.TypeList,
+			Description: "Functions enabled on the appliance, if the appliance is healthy. The Controller reports the status per appliance, not per function.",
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"cpu": {
+			Type:        schema.TypeFloat,
+			Description: "CPU usage in percent.",
+			Computed:    true,
+		},
+		"memory": {
+			Type:        schema.TypeFloat,
+			Description: "Memory usage in percent.",
+			Computed:    true,
+		},
+		"disk": {
+			Type:        schema.TypeFloat,
+			Description: "Disk usage in percent.",
+			Computed:    true,
+		},
+		"number_of_sessions": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"details": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"upgrade_status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"upgrade_details": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"functions": {
+			Type:        schema.TypeList,
+			Description: "Functions enabled on the appliance.",
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// applianceStatusFunctions returns the functions enabled on the appliance.
+func applianceStatusFunctions(a openapi.ApplianceWithStatus) []string {
+	functions := make([]string, 0)
+	if v := a.GetController(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionController)
+	}
+	if v := a.GetGateway(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionGateway)
+	}
+	if v := a.GetLogServer(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionLogServer)
+	}
+	if v := a.GetLogForwarder(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionLogForwarder)
+	}
+	if v := a.GetConnector(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionConnector)
+	}
+	if v := a.GetPortal(); v.GetEnabled() {
+		functions = append(functions, applianceFunctionPortal)
+	}
+	return functions
+}
+
+// applianceHealth returns true and the enabled functions if the appliance is online, its status is healthy
+// and it has at least one function enabled. The Controller has no status per function, so all functions of
+// a healthy appliance are healthy.
+func applianceHealth(online bool, status string, functions []string) (bool, []string) {
+	if !online || status != ApplianceStatusHealthy || len(functions) == 0 {
+		return false, []string{}
+	}
+	return true, functions
+}
+
+func flattenApplianceStatus(a openapi.ApplianceWithStatus) map[string]interface{} {
+	functions := applianceStatusFunctions(a)
+	healthy, healthyFunctions := applianceHealth(a.GetOnline(), a.GetStatus(), functions)
+	upgrade := a.GetUpgrade()
+	return map[string]interface{}{
+		"appliance_id":       a.GetId(),
+		"appliance_name":     a.GetName(),
+		"site":               a.GetSite(),
+		"state":              a.GetState(),
+		"status":             a.GetStatus(),
+		"online":             a.GetOnline(),
+		"healthy":            healthy,
+		"healthy_functions":  healthyFunctions,
+		"version":            a.GetVersion(),
+		"cpu":                float64(a.GetCpu()),
+		"memory":             float64(a.GetMemory()),
+		"disk":               float64(a.GetDisk()),
+		"number_of_sessions": int(a.GetNumberOfSessions()),
+		"details":            a.GetDetails(),
+		"upgrade_status":     upgrade.GetStatus(),
+		"upgrade_details":    upgrade.GetDetails(),
+		"functions":          functions,
+	}
+}
+
+func dataSourceAppgateApplianceStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source Appliance status")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	appliance, diags := ResolveApplianceFromResourceData(ctx, d, api, token)
+	if diags != nil {
+		return diags
+	}
+	status, err := getApplianceStatus(ctx, meta, appliance.GetId())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(appliance.GetId())
+	for k, v := range flattenApplianceStatus(*status) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(fmt.Errorf("Failed to set %s, %w", k, err))
+		}
+	}
+	return nil
+}
+
+func dataSourceAppgateApplianceStatusesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source Appliance statuses")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	stats, _, err := api.AppliancesStatusGet(context.WithValue(ctx, openapi.ContextAccessToken, token)).Execute()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to get appliance status, %w", prettyPrintAPIError(err)))
+	}
+	siteID := d.Get("site_id").(string)
+	function := d.Get("function").(string)
+	appliances := make([]map[string]interface{}, 0)
+	healthyCount := 0
+	for _, a := range stats.GetData() {
+		if len(siteID) > 0 && a.GetSite() != siteID {
+			continue
+		}
+		if len(function) > 0 && !inArray(function, applianceStatusFunctions(a)) {
+			continue
+		}
+		status := flattenApplianceStatus(a)
+		if status["healthy"].(bool) {
+			healthyCount++
+		}
+		appliances = append(appliances, status)
+	}
+	if err := d.Set("appliances", appliances); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("healthy_count", healthyCount)
+	d.SetId(fmt.Sprintf("%s/%s", siteID, function))
+	return nil
+}
@@ -0,0 +1,67 @@
+package appgate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAppgateApplianceStatusDataSource(t *testing.T) {
+	dataSourceName := "data.appgatesdp_appliance_status.controller"
+	pluralDataSourceName := "data.appgatesdp_appliance_statuses.controllers"
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccApplianceStatusDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(pluralDataSourceName, "appliances.0.appliance_id"),
+					resource.TestCheckResourceAttr(pluralDataSourceName, "appliances.0.functions.0", "controller"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "appliance_id", pluralDataSourceName, "appliances.0.appliance_id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "appliance_name", pluralDataSourceName, "appliances.0.appliance_name"),
+					resource.TestCheckResourceAttr(dataSourceName, "state", ApplianceStateControllerReady),
+					resource.TestCheckResourceAttr(dataSourceName, "online", "true"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "version"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "status"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "healthy"),
+				),
+			},
+		},
+	})
+}
+
+func TestApplianceHealth(t *testing.T) {
+	gateway := []string{applianceFunctionGateway}
+	tests := []struct {
+		name      string
+		online    bool
+		status    string
+		functions []string
+		healthy   bool
+		want      []string
+	}{
+		{"healthy gateway", true, ApplianceStatusHealthy, gateway, true, gateway},
+		{"offline", false, ApplianceStatusHealthy, gateway, false, []string{}},
+		{"warning", true, "warning", gateway, false, []string{}},
+		{"no functions", true, ApplianceStatusHealthy, []string{}, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthy, functions := applianceHealth(tt.online, tt.status, tt.functions)
+			if healthy != tt.healthy || !reflect.DeepEqual(functions, tt.want) {
+				t.Errorf("applianceHealth() = %t, %v, want %t, %v", healthy, functions, tt.healthy, tt.want)
+			}
+		})
+	}
+}
+
+const testAccApplianceStatusDataSourceConfig = `
+data "appgatesdp_appliance_statuses" "controllers" {
+  function = "controller"
+}
+data "appgatesdp_appliance_status" "controller" {
+  appliance_id = data.appgatesdp_appliance_statuses.controllers.appliances[0].appliance_id
+}
+`
@@ -0,0 +1,91 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAppgateBlacklistedUsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateBlacklistedUsersRead,
+		Schema: map[string]*schema.Schema{
+			"provider_name": {
+				Type:        schema.TypeString,
+				Description: "Only include users from this identity provider.",
+				Optional:    true,
+			},
+			"blacklisted_users": {
+				Type:        schema.TypeList,
+				Description: "Users on the blacklist.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provider_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"blacklisted_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppgateBlacklistedUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source blacklisted users")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.BlacklistedUsersApi
+	list, _, err := api.BlacklistGet(context.WithValue(ctx, openapi.ContextAccessToken, token)).Execute()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to list blacklisted users, %w", prettyPrintAPIError(err)))
+	}
+	providerName := d.Get("provider_name").(string)
+	users := make([]map[string]interface{}, 0)
+	for _, entry := range list.GetData() {
+		if len(providerName) > 0 && entry.GetProviderName() != providerName {
+			continue
+		}
+		user := map[string]interface{}{
+			"user_distinguished_name": entry.GetUserDistinguishedName(),
+			"username":                entry.GetUsername(),
+			"provider_name":           entry.GetProviderName(),
+			"reason":                  entry.GetReason(),
+			"blacklisted_at":          "",
+		}
+		if v, ok := entry.GetBlacklistedAtOk(); ok {
+			user["blacklisted_at"] = v.UTC().Format(time.RFC3339)
+		}
+		users = append(users, user)
+	}
+	if err := d.Set("blacklisted_users", users); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strings.Join([]string{"blacklisted_users", providerName}, "/"))
+	return nil
+}
@@ -1,6 +1,7 @@
 package appgate
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -12,18 +13,20 @@ import (
 func dataSourceAppgateIdentityProvider() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceAppgateIdentityProviderRead,
-		Schema: map[string]*schema.Schema{
+		Schema: mergeSchemaMaps(identityProviderDataSchema(), map[string]*schema.Schema{
 			"identity_provider_id": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				Computed:      true,
 				ConflictsWith: []string{"identity_provider_name"},
 			},
 			"identity_provider_name": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				Computed:      true,
 				ConflictsWith: []string{"identity_provider_id"},
 			},
-		},
+		}),
 	}
 }
 
@@ -42,7 +45,6 @@ func dataSourceAppgateIdentityProviderRead(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("please provide one of identity_provider_id or identity_provider_name attributes")
 	}
 	var reqErr error
-	// var provider *openapi.BaseIdentityProvider
 	var provider map[string]interface{}
 	if iok {
 		provider, reqErr = findIdentityProviderByUUID(api, providerID.(string), token)
@@ -52,11 +54,16 @@ func dataSourceAppgateIdentityProviderRead(d *schema.ResourceData, meta interfac
 	if reqErr != nil {
 		return reqErr
 	}
-	log.Printf("[DEBUG] Got identity provider: %+v", provider)
+	log.Printf("[DEBUG] Got identity provider: %s %s", provider["id"], provider["name"])
 
 	d.SetId(provider["id"].(string))
 	d.Set("identity_provider_name", provider["name"].(string))
 	d.Set("identity_provider_id", provider["id"].(string))
+	for k, v := range flattenIdentityProviderData(provider) {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("Failed to set %s, %w", k, err)
+		}
+	}
 	return nil
 }
 
@@ -70,14 +77,248 @@ func findIdentityProviderByUUID(api *openapi.IdentityProvidersApiService, id str
 }
 
 func findIdentityProviderByName(api *openapi.IdentityProvidersApiService, name string, token string) (map[string]interface{}, error) {
-	ctx := BaseAuthContext(token)
-	request := api.IdentityProvidersGet(ctx)
-	provider, _, err := request.Query(name).OrderBy("name").Range_("0-1").Execute()
+	providers, err := listIdentityProviders(BaseAuthContext(token), api, name)
 	if err != nil {
 		return nil, err
 	}
-	for _, s := range provider.GetData() {
-		return s, nil
+	// the query is a partial match, so "local" also matches "local-ad".
+	for _, s := range providers {
+		if s["name"] == name {
+			return s, nil
+		}
 	}
 	return nil, fmt.Errorf("Failed to find identity provider %s", name)
 }
+
+// identityProvidersPageSize is the number of identity providers requested per page by listIdentityProviders.
+const identityProvidersPageSize = 100
+
+// listIdentityProviders returns all identity providers that match query, ordered by name.
+// The identity providers are requested a page at a time, until a page is not full.
+func listIdentityProviders(ctx context.Context, api *openapi.IdentityProvidersApiService, query string) ([]map[string]interface{}, error) {
+	providers := make([]map[string]interface{}, 0)
+	for start := 0; ; start += identityProvidersPageSize {
+		request := api.IdentityProvidersGet(ctx).OrderBy("name").Range_(identityProvidersRange(start))
+		if len(query) > 0 {
+			request = request.Query(query)
+		}
+		list, _, err := request.Execute()
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, list.GetData()...)
+		if len(list.GetData()) < identityProvidersPageSize {
+			return providers, nil
+		}
+	}
+}
+
+// identityProvidersRange returns the range of the page that starts at start, the end of the range is inclusive.
+func identityProvidersRange(start int) string {
+	return fmt.Sprintf("%d-%d", start, start+identityProvidersPageSize-1)
+}
+
+// identityProviderDataAttribute maps a top level attribute in the identity provider JSON to the data source schema.
+type identityProviderDataAttribute struct {
+	json string
+	key  string
+	typ  schema.ValueType
+}
+
+// identityProviderDataAttributes is the scalar and list of strings attributes for all identity provider types.
+// Secrets, such as admin_password and shared_secret, are never returned by the controller.
+var identityProviderDataAttributes = []identityProviderDataAttribute{
+	{"type", "type", schema.TypeString},
+	{"notes", "notes", schema.TypeString},
+	{"tags", "tags", schema.TypeList},
+	{"adminProvider", "admin_provider", schema.TypeBool},
+	{"deviceLimitPerUser", "device_limit_per_user", schema.TypeInt},
+	{"inactivityTimeoutMinutes", "inactivity_timeout_minutes", schema.TypeInt},
+	{"networkInactivityTimeoutEnabled", "network_inactivity_timeout_enabled", schema.TypeBool},
+	{"ipPoolV4", "ip_pool_v4", schema.TypeString},
+	{"ipPoolV6", "ip_pool_v6", schema.TypeString},
+	{"userScripts", "user_scripts", schema.TypeList},
+	{"dnsServers", "dns_servers", schema.TypeList},
+	{"dnsSearchDomains", "dns_search_domains", schema.TypeList},
+	{"blockLocalDnsRequests", "block_local_dns_requests", schema.TypeBool},
+	// LDAP, LDAP certificate and RADIUS
+	{"hostnames", "hostnames", schema.TypeList},
+	{"port", "port", schema.TypeInt},
+	{"sslEnabled", "ssl_enabled", schema.TypeBool},
+	{"adminDistinguishedName", "admin_distinguished_name", schema.TypeString},
+	{"baseDn", "base_dn", schema.TypeString},
+	{"objectClass", "object_class", schema.TypeString},
+	{"userFilter", "user_filter", schema.TypeString},
+	{"usernameAttribute", "username_attribute", schema.TypeString},
+	{"membershipFilter", "membership_filter", schema.TypeString},
+	{"membershipBaseDn", "membership_base_dn", schema.TypeString},
+	{"caCertificates", "ca_certificates", schema.TypeList},
+	{"certificateUserAttribute", "certificate_user_attribute", schema.TypeString},
+	{"certificateAttribute", "certificate_attribute", schema.TypeString},
+	{"skipX509ExternalChecks", "skip_x509_external_checks", schema.TypeBool},
+	{"authenticationProtocol", "authentication_protocol", schema.TypeString},
+	// Local database
+	{"userLockoutThreshold", "user_lockout_threshold", schema.TypeInt},
+	{"minPasswordLength", "min_password_length", schema.TypeInt},
+	// SAML and OIDC
+	{"redirectUrl", "redirect_url", schema.TypeString},
+	{"issuer", "issuer", schema.TypeString},
+	{"audience", "audience", schema.TypeString},
+	{"providerCertificate", "provider_certificate", schema.TypeString},
+	{"forceAuthn", "force_authn", schema.TypeBool},
+	{"scope", "scope", schema.TypeString},
+}
+
+// identityProviderDataSchema is the computed identity provider attributes shared by the single and plural data source.
+func identityProviderDataSchema() map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema, len(identityProviderDataAttributes)+5)
+	for _, a := range identityProviderDataAttributes {
+		s[a.key] = &schema.Schema{
+			Type:     a.typ,
+			Computed: true,
+		}
+		if a.typ == schema.TypeList {
+			s[a.key].Elem = &schema.Schema{Type: schema.TypeString}
+		}
+	}
+	computed := func(attributes map[string]schema.ValueType) *schema.Schema {
+		elem := make(map[string]*schema.Schema, len(attributes))
+		for k, t := range attributes {
+			elem[k] = &schema.Schema{Type: t, Computed: true}
+		}
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Resource{Schema: elem},
+		}
+	}
+	s["claim_mappings"] = computed(map[string]schema.ValueType{
+		"attribute_name": schema.TypeString,
+		"claim_name":     schema.TypeString,
+		"list":           schema.TypeBool,
+		"encrypt":        schema.TypeBool,
+	})
+	s["on_demand_claim_mappings"] = computed(map[string]schema.ValueType{
+		"command":    schema.TypeString,
+		"claim_name": schema.TypeString,
+		"platform":   schema.TypeString,
+		"name":       schema.TypeString,
+		"path":       schema.TypeString,
+		"args":       schema.TypeString,
+	})
+	s["on_boarding_two_factor"] = computed(map[string]schema.ValueType{
+		"mfa_provider_id":       schema.TypeString,
+		"message":               schema.TypeString,
+		"device_limit_per_user": schema.TypeInt,
+		"claim_suffix":          schema.TypeString,
+		"always_required":       schema.TypeBool,
+	})
+	s["password_warning"] = computed(map[string]schema.ValueType{
+		"enabled":        schema.TypeBool,
+		"threshold_days": schema.TypeInt,
+		"message":        schema.TypeString,
+	})
+	s["google"] = computed(map[string]schema.ValueType{
+		"enabled":       schema.TypeBool,
+		"refresh_token": schema.TypeBool,
+	})
+	return s
+}
+
+// flattenIdentityProviderData flattens the identity provider JSON from GET /identity-providers
+// into the attributes in identityProviderDataSchema, attributes that are not used by the
+// identity provider type are set to their zero value.
+func flattenIdentityProviderData(provider map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(identityProviderDataAttributes)+5)
+	for _, a := range identityProviderDataAttributes {
+		out[a.key] = identityProviderDataValue(provider[a.json], a.typ)
+	}
+
+	object := func(in interface{}, attributes map[string]string, types map[string]schema.ValueType) map[string]interface{} {
+		m, _ := in.(map[string]interface{})
+		row := make(map[string]interface{}, len(attributes))
+		for json, key := range attributes {
+			row[key] = identityProviderDataValue(m[json], types[key])
+		}
+		return row
+	}
+	list := func(in interface{}, attributes map[string]string, types map[string]schema.ValueType) []interface{} {
+		items, _ := in.([]interface{})
+		rows := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			rows = append(rows, object(item, attributes, types))
+		}
+		return rows
+	}
+	single := func(in interface{}, attributes map[string]string, types map[string]schema.ValueType) []interface{} {
+		if _, ok := in.(map[string]interface{}); !ok {
+			return []interface{}{}
+		}
+		return []interface{}{object(in, attributes, types)}
+	}
+
+	out["claim_mappings"] = list(provider["claimMappings"],
+		map[string]string{"attributeName": "attribute_name", "claimName": "claim_name", "list": "list", "encrypt": "encrypt"},
+		map[string]schema.ValueType{"attribute_name": schema.TypeString, "claim_name": schema.TypeString, "list": schema.TypeBool, "encrypt": schema.TypeBool},
+	)
+
+	onDemand := make([]interface{}, 0)
+	items, _ := provider["onDemandClaimMappings"].([]interface{})
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		parameters, _ := m["parameters"].(map[string]interface{})
+		onDemand = append(onDemand, map[string]interface{}{
+			"command":    identityProviderDataValue(m["command"], schema.TypeString),
+			"claim_name": identityProviderDataValue(m["claimName"], schema.TypeString),
+			"platform":   identityProviderDataValue(m["platform"], schema.TypeString),
+			"name":       identityProviderDataValue(parameters["name"], schema.TypeString),
+			"path":       identityProviderDataValue(parameters["path"], schema.TypeString),
+			"args":       identityProviderDataValue(parameters["args"], schema.TypeString),
+		})
+	}
+	out["on_demand_claim_mappings"] = onDemand
+
+	out["on_boarding_two_factor"] = single(provider["onBoarding2FA"],
+		map[string]string{"mfaProviderId": "mfa_provider_id", "message": "message", "deviceLimitPerUser": "device_limit_per_user", "claimSuffix": "claim_suffix", "alwaysRequired": "always_required"},
+		map[string]schema.ValueType{"mfa_provider_id": schema.TypeString, "message": schema.TypeString, "device_limit_per_user": schema.TypeInt, "claim_suffix": schema.TypeString, "always_required": schema.TypeBool},
+	)
+	out["password_warning"] = single(provider["passwordWarning"],
+		map[string]string{"enabled": "enabled", "thresholdDays": "threshold_days", "message": "message"},
+		map[string]schema.ValueType{"enabled": schema.TypeBool, "threshold_days": schema.TypeInt, "message": schema.TypeString},
+	)
+	out["google"] = single(provider["google"],
+		map[string]string{"enabled": "enabled", "refreshToken": "refresh_token"},
+		map[string]schema.ValueType{"enabled": schema.TypeBool, "refresh_token": schema.TypeBool},
+	)
+	return out
+}
+
+// identityProviderDataValue converts a JSON value to the schema type, or the zero value if it is missing.
+func identityProviderDataValue(v interface{}, typ schema.ValueType) interface{} {
+	switch typ {
+	case schema.TypeBool:
+		b, _ := v.(bool)
+		return b
+	case schema.TypeInt:
+		switch n := v.(type) {
+		case float64:
+			return int(n)
+		case int:
+			return n
+		case int32:
+			return int(n)
+		}
+		return 0
+	case schema.TypeList:
+		items, _ := v.([]interface{})
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	s, _ := v.(string)
+	return s
+}
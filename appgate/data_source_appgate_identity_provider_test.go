@@ -1,9 +1,11 @@
 package appgate
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccAppgateIdentityProviderDataSource(t *testing.T) {
@@ -25,3 +27,105 @@ func TestAccAppgateIdentityProviderDataSource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAppgateIdentityProvidersDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "appgatesdp_identity_providers" "local" {
+  type = "LocalDatabase"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.appgatesdp_identity_providers.local", "identity_providers.#", "1"),
+					resource.TestCheckResourceAttr("data.appgatesdp_identity_providers.local", "identity_providers.0.name", "local"),
+					resource.TestCheckResourceAttr("data.appgatesdp_identity_providers.local", "identity_providers.0.type", "LocalDatabase"),
+					resource.TestCheckResourceAttrSet("data.appgatesdp_identity_providers.local", "identity_providers.0.ip_pool_v4"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenIdentityProviderData(t *testing.T) {
+	var provider map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+  "id": "6b8a8cf4-2f8c-4c33-9fa3-6b0e0e2b3d7a",
+  "name": "ad",
+  "type": "Ldap",
+  "tags": ["terraform"],
+  "adminProvider": true,
+  "inactivityTimeoutMinutes": 28,
+  "ipPoolV4": "ip4-pool",
+  "userScripts": ["script-1"],
+  "hostnames": ["dc.ad.company.com"],
+  "port": 389,
+  "sslEnabled": true,
+  "baseDn": "OU=Users,DC=company,DC=com",
+  "onBoarding2FA": {"mfaProviderId": "fido", "message": "welcome", "deviceLimitPerUser": 3, "claimSuffix": "onBoarding"},
+  "passwordWarning": {"enabled": true, "thresholdDays": 13, "message": "expires soon"},
+  "claimMappings": [{"attributeName": "mail", "claimName": "emails", "list": true, "encrypt": false}],
+  "onDemandClaimMappings": [{"command": "fileSize", "claimName": "antiVirusIsRunning", "parameters": {"path": "/usr/bin/python3"}, "platform": "desktop.windows.all"}]
+}`), &provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, dataSourceAppgateIdentityProvider().Schema, map[string]interface{}{})
+	for k, v := range flattenIdentityProviderData(provider) {
+		if err := d.Set(k, v); err != nil {
+			t.Fatalf("d.Set(%q): %s", k, err)
+		}
+	}
+	want := map[string]interface{}{
+		"type":                       "Ldap",
+		"admin_provider":             true,
+		"inactivity_timeout_minutes": 28,
+		"port":                       389,
+		"ssl_enabled":                true,
+		"ip_pool_v6":                 "",
+		"tags.0":                     "terraform",
+		"hostnames.0":                "dc.ad.company.com",
+		"on_boarding_two_factor.0.mfa_provider_id":       "fido",
+		"on_boarding_two_factor.0.device_limit_per_user": 3,
+		"password_warning.0.threshold_days":              13,
+		"claim_mappings.0.attribute_name":                "mail",
+		"claim_mappings.0.list":                          true,
+		"on_demand_claim_mappings.0.path":                "/usr/bin/python3",
+		"google.#":                                       0,
+	}
+	for k, v := range want {
+		if got := d.Get(k); got != v {
+			t.Errorf("%s = %v, want %v", k, got, v)
+		}
+	}
+}
+
+func TestFlattenIdentityProviderDataLocalDatabase(t *testing.T) {
+	var provider map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+  "id": "3d3ad0ab-4b8c-4d8a-9d2b-7e2d2b5d0c11",
+  "name": "local",
+  "type": "LocalDatabase",
+  "userLockoutThreshold": 5,
+  "minPasswordLength": 12
+}`), &provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := flattenIdentityProviderData(provider)
+	if got["user_lockout_threshold"] != 5 || got["min_password_length"] != 12 {
+		t.Fatalf("user_lockout_threshold = %v, min_password_length = %v, want 5 and 12", got["user_lockout_threshold"], got["min_password_length"])
+	}
+}
+
+func TestIdentityProvidersRange(t *testing.T) {
+	if got := identityProvidersRange(0); got != "0-99" {
+		t.Errorf("identityProvidersRange(0) = %s, want 0-99", got)
+	}
+	if got := identityProvidersRange(identityProvidersPageSize); got != "100-199" {
+		t.Errorf("identityProvidersRange(100) = %s, want 100-199", got)
+	}
+}
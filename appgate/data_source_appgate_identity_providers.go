@@ -0,0 +1,97 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAppgateIdentityProviders() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateIdentityProvidersRead,
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Description: "Only include identity providers of this type.",
+				Optional:    true,
+				ValidateFunc: validation.StringInSlice([]string{
+					identityProviderLocalDatabase,
+					identityProviderRadius,
+					identityProviderLdap,
+					identityProviderSaml,
+					identityProviderLdapCertificate,
+					identityProviderConnector,
+					identityProviderOidc,
+				}, false),
+			},
+			"tag": {
+				Type:        schema.TypeString,
+				Description: "Only include identity providers with this tag.",
+				Optional:    true,
+			},
+			"identity_providers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: mergeSchemaMaps(identityProviderDataSchema(), map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppgateIdentityProvidersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source identity providers")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.IdentityProvidersApi
+	list, err := listIdentityProviders(BaseAuthContext(token), api, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to list identity providers, %w", prettyPrintAPIError(err)))
+	}
+	providerType := d.Get("type").(string)
+	tag := d.Get("tag").(string)
+	providers := make([]map[string]interface{}, 0)
+	for _, p := range list {
+		provider := flattenIdentityProviderData(p)
+		if len(providerType) > 0 && provider["type"] != providerType {
+			continue
+		}
+		if len(tag) > 0 && !identityProviderHasTag(provider, tag) {
+			continue
+		}
+		provider["id"], _ = p["id"].(string)
+		provider["name"], _ = p["name"].(string)
+		providers = append(providers, provider)
+	}
+	if err := d.Set("identity_providers", providers); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strings.Join([]string{"identity_providers", providerType, tag}, "/"))
+	return nil
+}
+
+func identityProviderHasTag(provider map[string]interface{}, tag string) bool {
+	for _, t := range provider["tags"].([]interface{}) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAppgateOtpSeeds() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateOtpSeedsRead,
+		Schema: mergeSchemaMaps(distinguishedNameFilterSchema(), map[string]*schema.Schema{
+			"otp_seeds": {
+				Type:        schema.TypeList,
+				Description: "Users with a time-based one-time password seed for the built-in TOTP provider.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provider_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceAppgateOtpSeedsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source OTP seeds")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.OtpApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	list, err := listAllPages(func(pageRange string) ([]map[string]interface{}, error) {
+		list, _, err := api.OtpSeedsGet(ctx).OrderBy("distinguishedName").Range_(pageRange).Execute()
+		if err != nil {
+			return nil, err
+		}
+		seeds := make([]map[string]interface{}, 0, len(list.GetData()))
+		for _, seed := range list.GetData() {
+			seeds = append(seeds, map[string]interface{}{
+				"distinguished_name": seed.GetDistinguishedName(),
+				"username":           seed.GetUsername(),
+				"provider_name":      seed.GetProviderName(),
+			})
+		}
+		return seeds, nil
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to list OTP seeds, %w", prettyPrintAPIError(err)))
+	}
+	filter := expandDistinguishedNameFilter(d)
+	seeds := make([]map[string]interface{}, 0)
+	for _, seed := range list {
+		if filter.matches(seed["distinguished_name"].(string), seed["username"].(string), seed["provider_name"].(string)) {
+			seeds = append(seeds, seed)
+		}
+	}
+	if err := d.Set("otp_seeds", seeds); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(filter.id("otp_seeds"))
+	return nil
+}
@@ -0,0 +1,87 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAppgateReferences() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateReferencesRead,
+		Schema: map[string]*schema.Schema{
+			"object_id": {
+				Type:         schema.TypeString,
+				Description:  "UUID of the object to look up references to.",
+				Optional:     true,
+				ValidateFunc: validation.IsUUID,
+				AtLeastOneOf: []string{"object_id", "tag"},
+			},
+			"object_name": {
+				Type:         schema.TypeString,
+				Description:  "Name of the object, used to find expressions that refer to the object by name, such as criteria scripts.",
+				Optional:     true,
+				RequiredWith: []string{"object_id"},
+			},
+			"tag": {
+				Type:         schema.TypeString,
+				Description:  "Tag to look up references to, such as policy entitlement_links.",
+				Optional:     true,
+				AtLeastOneOf: []string{"object_id", "tag"},
+			},
+			"references": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"attribute": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppgateReferencesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	q := referenceQuery{
+		ID:   d.Get("object_id").(string),
+		Name: d.Get("object_name").(string),
+		Tag:  d.Get("tag").(string),
+	}
+	refs, err := findReferences(ctx, meta, q)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	references := make([]map[string]interface{}, 0, len(refs))
+	for _, r := range refs {
+		references = append(references, map[string]interface{}{
+			"type":      r.Type,
+			"id":        r.ID,
+			"name":      r.Name,
+			"attribute": r.Attribute,
+		})
+	}
+	if err := d.Set("references", references); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", q.ID, q.Tag))
+	return nil
+}
@@ -0,0 +1,61 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAppgateReferencesDataSource(t *testing.T) {
+	dataSourceName := "data.appgatesdp_references.condition"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckItemDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccReferencesDataSourceConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "references.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "references.0.type", "appgatesdp_entitlement"),
+					resource.TestCheckResourceAttr(dataSourceName, "references.0.name", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "references.0.attribute", "conditions"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "references.0.id", "appgatesdp_entitlement.test_entitlement", "id"),
+					resource.TestCheckResourceAttr("appgatesdp_condition.test_condition", "prevent_destroy_if_referenced", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccReferencesDataSourceConfig(rName string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_site" "default_site" {
+  site_name = "Default Site"
+}
+resource "appgatesdp_condition" "test_condition" {
+  name                          = "%[1]s"
+  expression                    = "return true;"
+  prevent_destroy_if_referenced = true
+}
+resource "appgatesdp_entitlement" "test_entitlement" {
+  name = "%[1]s"
+  site = data.appgatesdp_site.default_site.id
+  conditions = [
+    appgatesdp_condition.test_condition.id
+  ]
+  actions {
+    action  = "allow"
+    subtype = "tcp_up"
+    hosts   = ["10.0.0.1"]
+    ports   = ["443"]
+  }
+}
+data "appgatesdp_references" "condition" {
+  object_id  = appgatesdp_condition.test_condition.id
+  depends_on = [appgatesdp_entitlement.test_entitlement]
+}
+`, rName)
+}
@@ -0,0 +1,102 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAppgateRegisteredDevices() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateRegisteredDevicesRead,
+		Schema: mergeSchemaMaps(distinguishedNameFilterSchema(), map[string]*schema.Schema{
+			"registered_devices": {
+				Type:        schema.TypeList,
+				Description: "Registered (on-boarded) devices.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"device_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provider_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"on_boarded_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceAppgateRegisteredDevicesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source registered devices")
+	filter := expandDistinguishedNameFilter(d)
+	devices, err := listRegisteredDevices(ctx, meta, filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	result := make([]map[string]interface{}, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, map[string]interface{}{
+			"distinguished_name": device.GetDistinguishedName(),
+			"device_id":          device.GetDeviceId(),
+			"username":           device.GetUsername(),
+			"provider_name":      device.GetProviderName(),
+			"hostname":           device.GetHostname(),
+			"on_boarded_at":      device.GetOnBoardedAt().UTC().Format(time.RFC3339),
+		})
+	}
+	if err := d.Set("registered_devices", result); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(filter.id("registered_devices"))
+	return nil
+}
+
+// listRegisteredDevices returns the registered devices that match filter, a page at a time.
+func listRegisteredDevices(ctx context.Context, meta interface{}, filter distinguishedNameFilter) ([]openapi.RegisteredDevice, error) {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API.RegisteredDevicesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	list, err := listAllPages(func(pageRange string) ([]openapi.RegisteredDevice, error) {
+		list, _, err := api.RegisteredDevicesGet(ctx).OrderBy("distinguishedName").Range_(pageRange).Execute()
+		return list.GetData(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list registered devices, %w", prettyPrintAPIError(err))
+	}
+	devices := make([]openapi.RegisteredDevice, 0)
+	for _, device := range list {
+		if filter.matches(device.GetDistinguishedName(), device.GetUsername(), device.GetProviderName()) {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
@@ -0,0 +1,142 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAppgateTokenRecords() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppgateTokenRecordsRead,
+		Schema: mergeSchemaMaps(distinguishedNameFilterSchema(), map[string]*schema.Schema{
+			"distinguished_names": {
+				Type:        schema.TypeList,
+				Description: "Devices with token records, a device is a user on a client.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provider_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"device_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_token_issued_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceAppgateTokenRecordsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Data source token records")
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.ActiveDevicesApi
+	list, _, err := api.TokenRecordsDnGet(context.WithValue(ctx, openapi.ContextAccessToken, token)).OrderBy("distinguishedName").Execute()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to list token records, %w", prettyPrintAPIError(err)))
+	}
+	filter := expandDistinguishedNameFilter(d)
+	records := make([]map[string]interface{}, 0)
+	for _, r := range list.GetData() {
+		if !filter.matches(r.GetDistinguishedName(), r.GetUsername(), r.GetProviderName()) {
+			continue
+		}
+		records = append(records, map[string]interface{}{
+			"distinguished_name":   r.GetDistinguishedName(),
+			"username":             r.GetUsername(),
+			"provider_name":        r.GetProviderName(),
+			"device_id":            r.GetDeviceId(),
+			"last_token_issued_at": r.GetLastTokenIssuedAt().UTC().Format(time.RFC3339),
+		})
+	}
+	if err := d.Set("distinguished_names", records); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(filter.id("token_records"))
+	return nil
+}
+
+// distinguishedNameFilter is the optional distinguished_name, username and provider_name filters
+// of the data sources that list users and devices.
+type distinguishedNameFilter struct {
+	distinguishedName string
+	username          string
+	providerName      string
+}
+
+func distinguishedNameFilterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"distinguished_name": {
+			Type:        schema.TypeString,
+			Description: "Only include this distinguished name. The distinguished name of a user, CN=<username>,OU=<provider name>, includes all devices of the user.",
+			Optional:    true,
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Description: "Only include this user.",
+			Optional:    true,
+		},
+		"provider_name": {
+			Type:        schema.TypeString,
+			Description: "Only include users from this identity provider.",
+			Optional:    true,
+		},
+	}
+}
+
+func expandDistinguishedNameFilter(d *schema.ResourceData) distinguishedNameFilter {
+	return distinguishedNameFilter{
+		distinguishedName: d.Get("distinguished_name").(string),
+		username:          d.Get("username").(string),
+		providerName:      d.Get("provider_name").(string),
+	}
+}
+
+func (f distinguishedNameFilter) matches(dn, username, providerName string) bool {
+	if len(f.distinguishedName) > 0 && !distinguishedNameMatches(dn, f.distinguishedName) {
+		return false
+	}
+	if len(f.username) > 0 && username != f.username {
+		return false
+	}
+	if len(f.providerName) > 0 && providerName != f.providerName {
+		return false
+	}
+	return true
+}
+
+// id returns a data source ID for the filter.
+func (f distinguishedNameFilter) id(prefix string) string {
+	return strings.Join([]string{prefix, f.distinguishedName, f.username, f.providerName}, "/")
+}
+
+// distinguishedNameMatches returns true if dn is filter, or a device of the user in filter.
+func distinguishedNameMatches(dn, filter string) bool {
+	dn, filter = strings.ToLower(dn), strings.ToLower(filter)
+	return dn == filter || strings.HasSuffix(dn, ","+filter)
+}
@@ -0,0 +1,449 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	entitlementActionAllow   = "allow"
+	entitlementActionBlock   = "block"
+	entitlementActionAlert   = "alert"
+	entitlementActionExclude = "exclude"
+
+	// unknownConfigValue is the placeholder the plugin SDK uses for values that
+	// are not known until apply, for example a host computed from another resource.
+	unknownConfigValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+)
+
+var (
+	hostnameRegex     = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9_]([a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9_])?\.)*[a-zA-Z0-9_]([a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9_])?\.?$`)
+	hostTemplateRegex = regexp.MustCompile(`\{\{.+\}\}`)
+)
+
+func entitlementActionTypes() []string {
+	return []string{entitlementActionAllow, entitlementActionBlock, entitlementActionAlert, entitlementActionExclude}
+}
+
+func entitlementActionSubtypes() []string {
+	return []string{
+		"icmp_up", "icmp_down", "icmpv6_up", "icmpv6_down",
+		"udp_up", "udp_down", "tcp_up", "tcp_down",
+		"ah_up", "ah_down", "esp_up", "esp_down",
+		"gre_up", "gre_down", "http_up",
+	}
+}
+
+// portSubtypes are the subtypes where ports are meaningful, tcp and udp require at least one.
+func portSubtypes() []string {
+	return []string{"udp_up", "udp_down", "tcp_up", "tcp_down", "http_up"}
+}
+
+func httpMethods() []string {
+	return []string{"GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+}
+
+// hostResolvers are the name resolver prefixes supported by the gateways, e.g. aws://tag:Name=web.
+func hostResolvers() []string {
+	return []string{"dns", "aws", "azure", "gcp", "esx", "illumio"}
+}
+
+// entitlementActionSchema is the schema of a single IP access action, shared by
+// appgatesdp_entitlement.actions and appgatesdp_entitlement_action.
+func entitlementActionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"subtype": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(entitlementActionSubtypes(), false),
+		},
+
+		"action": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(entitlementActionTypes(), false),
+		},
+
+		"hosts": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Set:      hashEntitlementActionHost,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateEntitlementActionHost,
+			},
+		},
+
+		"ports": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Set:      hashEntitlementActionPort,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateEntitlementActionPort,
+			},
+		},
+
+		"types": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateEntitlementActionType,
+			},
+		},
+
+		"methods": {
+			Type:             schema.TypeSet,
+			Optional:         true,
+			Computed:         true,
+			DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
+			Set:              hashEntitlementActionMethod,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(httpMethods(), true),
+			},
+		},
+
+		"monitor": {
+			Type:             schema.TypeList,
+			MaxItems:         1,
+			Optional:         true,
+			Computed:         true,
+			DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"enabled": {
+						Type:             schema.TypeBool,
+						Optional:         true,
+						Computed:         true,
+						DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
+					},
+					"timeout": {
+						Type:             schema.TypeInt,
+						Optional:         true,
+						Computed:         true,
+						DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateEntitlementActionHost is used as ValidateFunc on each entitlement action host.
+func validateEntitlementActionHost(v interface{}, k string) (warns []string, errs []error) {
+	host, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+	if host == unknownConfigValue {
+		return
+	}
+	if err := checkEntitlementActionHost(host); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", k, err))
+	}
+	return
+}
+
+// validateEntitlementActionPort is used as ValidateFunc on each entitlement action port.
+func validateEntitlementActionPort(v interface{}, k string) (warns []string, errs []error) {
+	port, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+	if port == unknownConfigValue {
+		return
+	}
+	if _, _, err := parseNumberRange(port, 0, 65535); err != nil {
+		errs = append(errs, fmt.Errorf("%s: invalid port %q, %w", k, port, err))
+	}
+	return
+}
+
+// validateEntitlementActionType is used as ValidateFunc on each entitlement action ICMP type.
+func validateEntitlementActionType(v interface{}, k string) (warns []string, errs []error) {
+	t, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+	if t == unknownConfigValue {
+		return
+	}
+	if _, _, err := parseNumberRange(t, 0, 255); err != nil {
+		errs = append(errs, fmt.Errorf("%s: invalid ICMP type %q, %w", k, t, err))
+	}
+	return
+}
+
+func checkEntitlementActionHost(host string) error {
+	if len(host) == 0 {
+		return errors.New("host can not be empty")
+	}
+	if strings.TrimSpace(host) != host || strings.ContainsAny(host, " \t\n") {
+		return fmt.Errorf("host %q can not contain whitespace", host)
+	}
+	// user claims are expanded on the controller, e.g. {{claims.user.ip}}
+	if hostTemplateRegex.MatchString(host) {
+		return nil
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if strings.Contains(host, "/") && !strings.Contains(host, "://") {
+		if _, _, err := net.ParseCIDR(host); err != nil {
+			return fmt.Errorf("invalid CIDR %q", host)
+		}
+		return nil
+	}
+	if i := strings.Index(host, "://"); i > 0 {
+		scheme, value := strings.ToLower(host[:i]), host[i+3:]
+		switch scheme {
+		case "http", "https":
+			u, err := url.Parse(host)
+			if err != nil || len(u.Host) == 0 {
+				return fmt.Errorf("invalid URL %q", host)
+			}
+			return nil
+		case "dns":
+			if !hostnameRegex.MatchString(value) {
+				return fmt.Errorf("invalid dns resolver hostname %q", host)
+			}
+			return nil
+		case "aws":
+			kind, filter, found := strings.Cut(value, ":")
+			if !found || len(kind) == 0 || len(filter) == 0 {
+				return fmt.Errorf("invalid aws resolver %q, expected aws://<type>:<value>, e.g. aws://tag:Name=web", host)
+			}
+			if strings.HasSuffix(kind, "tag") {
+				if key, _, found := strings.Cut(filter, "="); !found || len(key) == 0 {
+					return fmt.Errorf("invalid aws resolver %q, expected aws://%s:<key>=<value>", host, kind)
+				}
+			}
+			return nil
+		}
+		if !inArray(scheme, hostResolvers()) {
+			return fmt.Errorf("unknown resolver %q in %q, expected one of %v", scheme, host, hostResolvers())
+		}
+		if len(value) == 0 {
+			return fmt.Errorf("resolver %q is missing a value", host)
+		}
+		return nil
+	}
+	if !hostnameRegex.MatchString(host) {
+		return fmt.Errorf("%q is not a valid IP, CIDR, hostname or resolver", host)
+	}
+	return nil
+}
+
+// parseNumberRange parses "80" or "1024-2048" and makes sure the values are
+// within min and max, and that the range is not reversed.
+func parseNumberRange(s string, min, max int) (int, int, error) {
+	first, last, isRange := strings.Cut(s, "-")
+	from, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected a number or range, got %q", s)
+	}
+	to := from
+	if isRange {
+		to, err = strconv.Atoi(strings.TrimSpace(last))
+		if err != nil {
+			return 0, 0, fmt.Errorf("expected a number or range, got %q", s)
+		}
+	}
+	if from < min || to > max {
+		return 0, 0, fmt.Errorf("expected value between %d and %d, got %q", min, max, s)
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("range start is greater than range end, got %q", s)
+	}
+	return from, to, nil
+}
+
+// normalizeNumberRange converts equivalent forms, "080" and "80-80" are both written as "80".
+func normalizeNumberRange(s string) string {
+	from, to, err := parseNumberRange(s, 0, 65535)
+	if err != nil {
+		return s
+	}
+	if from == to {
+		return strconv.Itoa(from)
+	}
+	return fmt.Sprintf("%d-%d", from, to)
+}
+
+// normalizeEntitlementActionHost converts a host to the form returned by the controller,
+// IP addresses are written in canonical form, hostnames and resolver prefixes are lower case.
+// The value of aws, azure, gcp, esx and illumio resolvers is case sensitive and left as is.
+func normalizeEntitlementActionHost(host string) string {
+	if hostTemplateRegex.MatchString(host) {
+		return host
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	if !strings.Contains(host, "://") {
+		if addr, prefix, found := strings.Cut(host, "/"); found {
+			if ip := net.ParseIP(addr); ip != nil {
+				return ip.String() + "/" + prefix
+			}
+			return host
+		}
+		return strings.ToLower(host)
+	}
+	i := strings.Index(host, "://")
+	scheme, value := strings.ToLower(host[:i]), host[i+3:]
+	switch scheme {
+	case "dns":
+		return scheme + "://" + strings.ToLower(value)
+	case "http", "https":
+		u, err := url.Parse(host)
+		if err != nil {
+			return host
+		}
+		u.Scheme = scheme
+		u.Host = strings.ToLower(u.Host)
+		return u.String()
+	}
+	return scheme + "://" + value
+}
+
+func normalizeStringSet(v interface{}, normalize func(string) string) []string {
+	var list []interface{}
+	switch t := v.(type) {
+	case *schema.Set:
+		list = t.List()
+	case []interface{}:
+		list = t
+	}
+	result := make([]string, 0, len(list))
+	for _, raw := range list {
+		s, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		result = append(result, normalize(s))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hashEntitlementActionHost(v interface{}) int {
+	return schema.HashString(normalizeEntitlementActionHost(v.(string)))
+}
+
+func hashEntitlementActionPort(v interface{}) int {
+	return schema.HashString(normalizeNumberRange(v.(string)))
+}
+
+func hashEntitlementActionMethod(v interface{}) int {
+	return schema.HashString(strings.ToUpper(v.(string)))
+}
+
+// validateEntitlementAction checks the combination of attributes within a single action,
+// where the single attribute ValidateFunc can't see the subtype.
+func validateEntitlementAction(raw map[string]interface{}) error {
+	var errs []error
+	subtype, _ := raw["subtype"].(string)
+	if len(subtype) == 0 || subtype == unknownConfigValue {
+		return nil
+	}
+	hosts := normalizeStringSet(raw["hosts"], func(s string) string { return s })
+	ports := normalizeStringSet(raw["ports"], func(s string) string { return s })
+	types := normalizeStringSet(raw["types"], func(s string) string { return s })
+	methods := normalizeStringSet(raw["methods"], func(s string) string { return s })
+
+	for _, host := range hosts {
+		if host == unknownConfigValue {
+			continue
+		}
+		if err := checkEntitlementActionHost(host); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		lower := strings.ToLower(host)
+		if subtype != "http_up" && (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")) {
+			errs = append(errs, fmt.Errorf("host %q is an URL, URLs are only valid for http_up, got %s", host, subtype))
+		}
+	}
+	if len(ports) > 0 && !inArray(subtype, portSubtypes()) {
+		errs = append(errs, fmt.Errorf("ports are only valid for %v, got %s with ports %v", portSubtypes(), subtype, ports))
+	}
+	if len(ports) == 0 && (strings.HasPrefix(subtype, "tcp_") || strings.HasPrefix(subtype, "udp_")) {
+		errs = append(errs, fmt.Errorf("%s requires at least one port", subtype))
+	}
+	for _, port := range ports {
+		if port == unknownConfigValue {
+			continue
+		}
+		if _, _, err := parseNumberRange(port, 0, 65535); err != nil {
+			errs = append(errs, fmt.Errorf("invalid port %q, %w", port, err))
+		}
+	}
+	if len(types) > 0 && !inArray(subtype, icmpTypes()) {
+		errs = append(errs, fmt.Errorf("types are only valid for %v, got %s with types %v", icmpTypes(), subtype, types))
+	}
+	for _, t := range types {
+		if t == unknownConfigValue {
+			continue
+		}
+		if _, _, err := parseNumberRange(t, 0, 255); err != nil {
+			errs = append(errs, fmt.Errorf("invalid ICMP type %q, %w", t, err))
+		}
+	}
+	if len(methods) > 0 && subtype != "http_up" {
+		errs = append(errs, fmt.Errorf("methods are only valid for http_up, got %s with methods %v", subtype, methods))
+	}
+	for _, method := range methods {
+		if method == unknownConfigValue {
+			continue
+		}
+		if !inArray(strings.ToUpper(method), httpMethods()) {
+			errs = append(errs, fmt.Errorf("invalid HTTP method %q, expected one of %v", method, httpMethods()))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s action: %w", subtype, errors.Join(errs...))
+	}
+	return nil
+}
+
+// resourceAppgateEntitlementActionsCustomizeDiff validates all actions at plan time,
+// instead of waiting for the controller to reject them during apply.
+func resourceAppgateEntitlementActionsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := d.GetOk("actions"); !ok && d.Get("actions_authoritative").(bool) {
+		return errors.New("at least one actions block is required, unless actions_authoritative is false")
+	}
+	// hosts or ports computed from other resources are validated once they are known.
+	if raw := d.GetRawConfig(); !raw.IsNull() && raw.IsKnown() && !raw.GetAttr("actions").IsWhollyKnown() {
+		return nil
+	}
+	v, ok := d.GetOk("actions")
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, action := range v.(*schema.Set).List() {
+		raw, ok := action.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := validateEntitlementAction(raw); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,225 @@
+package appgate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckEntitlementActionHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{host: "10.0.0.1"},
+		{host: "2001:db8::1"},
+		{host: "10.0.0.0/24"},
+		{host: "hostname.company.com"},
+		{host: "*.company.com"},
+		{host: "dns://hostname.company.com"},
+		{host: "aws://tag:Name=web"},
+		{host: "aws://security-group:web-sg"},
+		{host: "azure://vm-tag:env=prod"},
+		{host: "http://10.0.5.160"},
+		{host: "{{claims.user.ip}}"},
+		{host: "", wantErr: true},
+		{host: "10.0.0.0/33", wantErr: true},
+		{host: "host name.com", wantErr: true},
+		{host: "dns://", wantErr: true},
+		{host: "dns://-invalid-.com", wantErr: true},
+		{host: "aws://web", wantErr: true},
+		{host: "aws://tag:web", wantErr: true},
+		{host: "foo://bar", wantErr: true},
+		{host: "gcp://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := checkEntitlementActionHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkEntitlementActionHost(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseNumberRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to int
+		wantErr  bool
+	}{
+		{in: "80", from: 80, to: 80},
+		{in: "1024-2048", from: 1024, to: 2048},
+		{in: "0-65535", from: 0, to: 65535},
+		{in: "8080-80", wantErr: true},
+		{in: "65536", wantErr: true},
+		{in: "http", wantErr: true},
+		{in: "80-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			from, to, err := parseNumberRange(tt.in, 0, 65535)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNumberRange(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if from != tt.from || to != tt.to {
+				t.Fatalf("parseNumberRange(%q) = %d, %d want %d, %d", tt.in, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestNormalizeEntitlementAction(t *testing.T) {
+	hosts := map[string]string{
+		"Hostname.Company.COM":       "hostname.company.com",
+		"DNS://Hostname.Company.com": "dns://hostname.company.com",
+		"aws://tag:Name=Web":         "aws://tag:Name=Web",
+		"2001:DB8:0:0::1":            "2001:db8::1",
+		"2001:DB8::/32":              "2001:db8::/32",
+		"HTTP://Example.com/Path":    "http://example.com/Path",
+	}
+	for in, want := range hosts {
+		if got := normalizeEntitlementActionHost(in); got != want {
+			t.Errorf("normalizeEntitlementActionHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+	ports := map[string]string{
+		"80":        "80",
+		"080":       "80",
+		"80-80":     "80",
+		"1024-2048": "1024-2048",
+	}
+	for in, want := range ports {
+		if got := normalizeNumberRange(in); got != want {
+			t.Errorf("normalizeNumberRange(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateEntitlementAction(t *testing.T) {
+	set := func(values ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, values)
+	}
+	tests := []struct {
+		name    string
+		action  map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "tcp_up with ports",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("10.0.0.1"),
+				"ports":   set("80", "443"),
+			},
+		},
+		{
+			name: "icmp_up with types",
+			action: map[string]interface{}{
+				"subtype": "icmp_up",
+				"hosts":   set("10.0.0.1"),
+				"types":   []interface{}{"0-16"},
+			},
+		},
+		{
+			name: "http_up with methods",
+			action: map[string]interface{}{
+				"subtype": "http_up",
+				"hosts":   set("http://10.0.5.160"),
+				"methods": set("GET", "post"),
+			},
+		},
+		{
+			name: "icmp_up with ports",
+			action: map[string]interface{}{
+				"subtype": "icmp_up",
+				"hosts":   set("10.0.0.1"),
+				"ports":   set("80"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp_up without ports",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("10.0.0.1"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp_up with reversed range",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("10.0.0.1"),
+				"ports":   set("8080-80"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "udp_up with types",
+			action: map[string]interface{}{
+				"subtype": "udp_up",
+				"hosts":   set("10.0.0.1"),
+				"ports":   set("53"),
+				"types":   []interface{}{"0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp_up with methods",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("10.0.0.1"),
+				"ports":   set("80"),
+				"methods": set("GET"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp_up with URL",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("https://example.com"),
+				"ports":   set("443"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed aws resolver",
+			action: map[string]interface{}{
+				"subtype": "tcp_up",
+				"hosts":   set("aws://tag:"),
+				"ports":   set("443"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntitlementAction(tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateEntitlementAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntitlementActionKey(t *testing.T) {
+	action := func(action string, hosts, ports []interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"subtype": "tcp_up",
+			"action":  action,
+			"hosts":   hosts,
+			"ports":   ports,
+		}
+	}
+	a := action("allow", []interface{}{"10.0.0.1", "Host.Company.com"}, []interface{}{"443", "80-80"})
+	b := action("block", []interface{}{"host.company.com", "10.0.0.1"}, []interface{}{"80", "443"})
+	if entitlementActionKey(a) != entitlementActionKey(b) {
+		t.Fatalf("entitlementActionKey(%v) = %q, want %q", b, entitlementActionKey(b), entitlementActionKey(a))
+	}
+	c := action("allow", []interface{}{"10.0.0.1"}, []interface{}{"443"})
+	if entitlementActionKey(a) == entitlementActionKey(c) {
+		t.Fatalf("entitlementActionKey(%v) = entitlementActionKey(%v)", a, c)
+	}
+}
@@ -0,0 +1,173 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ephemeralApplianceSeed exports the seed file of an inactive appliance without storing it,
+// or the SSH password, in the plan or the state. Requires Terraform 1.10 or later.
+type ephemeralApplianceSeed struct {
+	ephemeralClient
+}
+
+type ephemeralApplianceSeedModel struct {
+	ApplianceID        types.String `tfsdk:"appliance_id"`
+	Activated          types.Bool   `tfsdk:"activated"`
+	ProvideCloudSSHKey types.Bool   `tfsdk:"provide_cloud_ssh_key"`
+	SSHKey             types.String `tfsdk:"ssh_key"`
+	Password           types.String `tfsdk:"password"`
+	OutputFormat       types.String `tfsdk:"output_format"`
+	SeedPath           types.String `tfsdk:"seed_path"`
+	SeedFile           types.String `tfsdk:"seed_file"`
+}
+
+var (
+	_ ephemeral.EphemeralResourceWithConfigure      = &ephemeralApplianceSeed{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &ephemeralApplianceSeed{}
+)
+
+func newEphemeralApplianceSeed() ephemeral.EphemeralResource {
+	return &ephemeralApplianceSeed{}
+}
+
+func (e *ephemeralApplianceSeed) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appliance_seed"
+}
+
+func (e *ephemeralApplianceSeed) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Seed file of an inactive appliance, which is never stored in the plan or the state.",
+		Attributes: map[string]schema.Attribute{
+			"appliance_id": schema.StringAttribute{
+				Description: "ID of the appliance.",
+				Required:    true,
+			},
+			"activated": schema.BoolAttribute{
+				Description: "True if the appliance is already activated, seed_file is empty for activated appliances.",
+				Computed:    true,
+			},
+			"provide_cloud_ssh_key": schema.BoolAttribute{
+				Description: "Use the SSH key provided by the cloud instance.",
+				Optional:    true,
+			},
+			"ssh_key": schema.StringAttribute{
+				Description: "SSH public key for the cz user.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "SSH password for the cz user.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"output_format": schema.StringAttribute{
+				Description: fmt.Sprintf("Format of seed_file, one of %s. Defaults to %s.", strings.Join(seedFormats(), ", "), seedFormatBase64),
+				Optional:    true,
+			},
+			"seed_path": schema.StringAttribute{
+				Description: fmt.Sprintf("Path of the seed file on the appliance, used by the cloud-init and ignition output formats. Defaults to %s.", defaultSeedPath),
+				Optional:    true,
+			},
+			"seed_file": schema.StringAttribute{
+				Description: "Seed file (json) generated from appliance, rendered in output_format.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (e *ephemeralApplianceSeed) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var config ephemeralApplianceSeedModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	configured := 0
+	for _, v := range []interface{ IsNull() bool }{config.ProvideCloudSSHKey, config.SSHKey, config.Password} {
+		if !v.IsNull() {
+			configured++
+		}
+	}
+	if configured > 1 {
+		resp.Diagnostics.AddError("Conflicting SSH configuration", "Only one of provide_cloud_ssh_key, ssh_key and password can be set.")
+	}
+	if format := config.OutputFormat; !format.IsNull() && !format.IsUnknown() && !inArray(format.ValueString(), seedFormats()) {
+		resp.Diagnostics.AddAttributeError(path.Root("output_format"), "Invalid output_format",
+			fmt.Sprintf("output_format must be one of %s, got %q", strings.Join(seedFormats(), ", "), format.ValueString()))
+	}
+}
+
+func (e *ephemeralApplianceSeed) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config ephemeralApplianceSeedModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if e.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The appgatesdp provider must be configured to export the appliance seed.")
+		return
+	}
+	token, err := e.client.GetToken()
+	if err != nil {
+		resp.Diagnostics.AddError("Could not authenticate", err.Error())
+		return
+	}
+	api := e.client.API.AppliancesApi
+	ctx = BaseAuthContext(token)
+	applianceID := config.ApplianceID.ValueString()
+
+	appliance, res, err := api.AppliancesIdGet(ctx, applianceID).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			resp.Diagnostics.AddAttributeError(path.Root("appliance_id"), "Appliance not found", fmt.Sprintf("Appliance %s does not exist", applianceID))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read Appliance", prettyPrintAPIError(err).Error())
+		return
+	}
+	config.Activated = types.BoolValue(appliance.GetActivated())
+	config.SeedFile = types.StringValue("")
+	if appliance.GetActivated() {
+		resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+		return
+	}
+
+	sshConfig := openapi.NewSSHConfig()
+	if !config.Password.IsNull() {
+		sshConfig.Password = openapi.PtrString(config.Password.ValueString())
+	}
+	if !config.SSHKey.IsNull() {
+		sshConfig.SshKey = openapi.PtrString(config.SSHKey.ValueString())
+	}
+	if !config.ProvideCloudSSHKey.IsNull() {
+		sshConfig.ProvideCloudSSHKey = openapi.PtrBool(config.ProvideCloudSSHKey.ValueBool())
+	}
+	seed, err := exportApplianceSeed(ctx, api, applianceID, *sshConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not export the appliance seed", err.Error())
+		return
+	}
+	format, seedPath := seedFormatBase64, defaultSeedPath
+	if !config.OutputFormat.IsNull() {
+		format = config.OutputFormat.ValueString()
+	}
+	if !config.SeedPath.IsNull() {
+		seedPath = config.SeedPath.ValueString()
+	}
+	rendered, err := renderApplianceSeed(seed, format, seedPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not render the appliance seed", err.Error())
+		return
+	}
+	config.SeedFile = types.StringValue(rendered)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+}
@@ -0,0 +1,77 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAppgateApplianceSeedEphemeral(t *testing.T) {
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resourceName := "appgatesdp_appliance.new_test_gateway"
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSeedEphemeral(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "activated", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSeedEphemeral(rName string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_site" "default_site" {
+  site_name = "Default Site"
+}
+
+resource "appgatesdp_appliance" "new_test_gateway" {
+  name     = "%s"
+  hostname = "envy-10-97-168-999.devops"
+
+  client_interface {
+    hostname       = "envy-10-97-168-130.devops"
+    proxy_protocol = true
+    https_port     = 447
+    dtls_port      = 445
+    allow_sources {
+      address = "1.3.3.8"
+      netmask = 32
+      nic     = "eth0"
+    }
+    override_spa_mode = "UDP-TCP"
+  }
+
+  site = data.appgatesdp_site.default_site.id
+  networking {
+    nics {
+      enabled = true
+      name    = "eth0"
+      ipv4 {
+        dhcp {
+          enabled = true
+          dns     = true
+          routers = true
+          ntp     = true
+        }
+      }
+    }
+  }
+}
+
+ephemeral "appgatesdp_appliance_seed" "test_gateway_seed_file" {
+  appliance_id  = appgatesdp_appliance.new_test_gateway.id
+  password      = "cz"
+  output_format = "cloud-init"
+}
+
+locals {
+  user_data = ephemeral.appgatesdp_appliance_seed.test_gateway_seed_file.seed_file
+}
+`, rName)
+}
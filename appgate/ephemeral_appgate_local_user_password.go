@@ -0,0 +1,148 @@
+package appgate
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const defaultLocalUserPasswordLength = 24
+
+// localUserPasswordCharSets are the character classes of a generated password, with at least
+// one character from each class.
+var localUserPasswordCharSets = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"0123456789",
+	"!#%&*+-=?@^_",
+}
+
+// ephemeralLocalUserPassword generates a password that meets the minimum password length of the
+// local database identity provider, to be set with password_wo on appgatesdp_local_users.
+// The password is never stored in the plan or the state.
+type ephemeralLocalUserPassword struct {
+	ephemeralClient
+}
+
+type ephemeralLocalUserPasswordModel struct {
+	Length            types.Int64  `tfsdk:"length"`
+	MinPasswordLength types.Int64  `tfsdk:"min_password_length"`
+	Result            types.String `tfsdk:"result"`
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &ephemeralLocalUserPassword{}
+
+func newEphemeralLocalUserPassword() ephemeral.EphemeralResource {
+	return &ephemeralLocalUserPassword{}
+}
+
+func (e *ephemeralLocalUserPassword) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_user_password"
+}
+
+func (e *ephemeralLocalUserPassword) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generated password for a local user, which is never stored in the plan or the state.",
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				Description: fmt.Sprintf("Length of the password. Defaults to %d, or min_password_length if it is longer.", defaultLocalUserPasswordLength),
+				Optional:    true,
+			},
+			"min_password_length": schema.Int64Attribute{
+				Description: "Minimum password length of the local database identity provider.",
+				Computed:    true,
+			},
+			"result": schema.StringAttribute{
+				Description: "The generated password.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (e *ephemeralLocalUserPassword) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config ephemeralLocalUserPasswordModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if e.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The appgatesdp provider must be configured to read the password policy of the local database.")
+		return
+	}
+	token, err := e.client.GetToken()
+	if err != nil {
+		resp.Diagnostics.AddError("Could not authenticate", err.Error())
+		return
+	}
+	api := e.client.API.LocalDatabaseIdentityProvidersApi
+	localDatabase, err := getBuiltinLocalDatabaseProviderUUID(BaseAuthContext(token), *api, token)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read LocalDatabase Identity provider", prettyPrintAPIError(err).Error())
+		return
+	}
+	minLength := int64(localDatabase.GetMinPasswordLength())
+
+	length := max(int64(defaultLocalUserPasswordLength), minLength)
+	if !config.Length.IsNull() {
+		length = config.Length.ValueInt64()
+	}
+	if length < minLength || length < int64(len(localUserPasswordCharSets)) {
+		resp.Diagnostics.AddAttributeError(path.Root("length"), "Password too short",
+			fmt.Sprintf("length %d is shorter than the minimum password length %d of the local database", length, max(minLength, int64(len(localUserPasswordCharSets)))))
+		return
+	}
+	password, err := generateLocalUserPassword(int(length))
+	if err != nil {
+		resp.Diagnostics.AddError("Could not generate password", err.Error())
+		return
+	}
+	config.Length = types.Int64Value(length)
+	config.MinPasswordLength = types.Int64Value(minLength)
+	config.Result = types.StringValue(password)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+}
+
+// generateLocalUserPassword returns a random password of length, with at least one character
+// of each of localUserPasswordCharSets.
+func generateLocalUserPassword(length int) (string, error) {
+	randomIndex := func(n int) (int, error) {
+		i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+		if err != nil {
+			return 0, err
+		}
+		return int(i.Int64()), nil
+	}
+	all := ""
+	for _, set := range localUserPasswordCharSets {
+		all += set
+	}
+	password := make([]byte, length)
+	for i := range password {
+		set := all
+		if i < len(localUserPasswordCharSets) {
+			set = localUserPasswordCharSets[i]
+		}
+		j, err := randomIndex(len(set))
+		if err != nil {
+			return "", err
+		}
+		password[i] = set[j]
+	}
+	// shuffle, so the required characters are not always first.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
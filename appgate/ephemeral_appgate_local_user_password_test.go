@@ -0,0 +1,71 @@
+package appgate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGenerateLocalUserPassword(t *testing.T) {
+	for _, length := range []int{4, 16, 64} {
+		password, err := generateLocalUserPassword(length)
+		if err != nil {
+			t.Fatalf("generateLocalUserPassword(%d) error = %v", length, err)
+		}
+		if len(password) != length {
+			t.Fatalf("generateLocalUserPassword(%d) = %q, want length %d", length, password, length)
+		}
+		for _, set := range localUserPasswordCharSets {
+			if !strings.ContainsAny(password, set) {
+				t.Fatalf("generateLocalUserPassword(%d) = %q, want a character of %q", length, password, set)
+			}
+		}
+	}
+}
+
+func TestAccLocalUsersGeneratedPassword(t *testing.T) {
+	resourceName := "appgatesdp_local_users.contractors"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckLocalUsersDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLocalUsersGeneratedPassword(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLocalUsersExist(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "user_ids.%", "2"),
+					resource.TestCheckNoResourceAttr(resourceName, "user.0.password_wo"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLocalUsersGeneratedPassword(rName string) string {
+	return fmt.Sprintf(`
+locals {
+  contractors = ["alice", "bob"]
+}
+
+ephemeral "appgatesdp_local_user_password" "contractor" {
+  for_each = toset(local.contractors)
+}
+
+resource "appgatesdp_local_users" "contractors" {
+  dynamic "user" {
+    for_each = local.contractors
+    content {
+      name                = "%s-${user.value}"
+      first_name          = user.value
+      last_name           = "contractor"
+      password_wo         = ephemeral.appgatesdp_local_user_password.contractor[user.value].result
+      password_wo_version = 1
+    }
+  }
+}
+`, rName)
+}
@@ -70,12 +70,14 @@ func identityProviderSchema() map[string]*schema.Schema {
 			"on_boarding_two_factor": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"mfa_provider_id": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Description: "ID or name of the MFA provider.",
+							Required:    true,
 						},
 						"message": {
 							Type:     schema.TypeString,
@@ -110,9 +112,11 @@ func identityProviderSchema() map[string]*schema.Schema {
 				Optional: true,
 			},
 			"user_scripts": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:        schema.TypeList,
+				Description: "IDs or names of the user claim scripts.",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"dns_servers": {
 				Type:     schema.TypeList,
@@ -137,12 +141,16 @@ func identityProviderSchema() map[string]*schema.Schema {
 func identityProviderIPPoolSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"ip_pool_v4": {
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:        schema.TypeString,
+			Description: "ID or name of the IPv4 pool.",
+			Optional:    true,
+			Computed:    true,
 		},
 		"ip_pool_v6": {
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:        schema.TypeString,
+			Description: "ID or name of the IPv6 pool.",
+			Optional:    true,
+			Computed:    true,
 		},
 	}
 }
@@ -338,7 +346,7 @@ func resourceIdentityProviderOnDemandClaimMappingsHash(v interface{}) int {
 // ldapProviderSchema return the default base schema for
 // LDAP and LDAP Certificate provider.
 func ldapProviderSchema() map[string]*schema.Schema {
-	s := identityProviderSchema()
+	s := mergeSchemaMaps(identityProviderSchema(), claimMappingPresetSchema())
 
 	s["hostnames"] = &schema.Schema{
 		Type:     schema.TypeList,
@@ -363,6 +371,9 @@ func ldapProviderSchema() map[string]*schema.Schema {
 		Sensitive: true,
 		Optional:  true,
 	}
+	for k, v := range writeOnlySecretSchema("admin_password", "admin_password", "Password of the service account used to bind to the LDAP servers.") {
+		s[k] = v
+	}
 	s["base_dn"] = &schema.Schema{
 		Type:     schema.TypeString,
 		Optional: true,
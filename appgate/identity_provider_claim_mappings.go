@@ -0,0 +1,262 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Claim mapping presets, the standard claim mappings for well-known directories.
+const (
+	claimMappingPresetActiveDirectory = "active_directory"
+	claimMappingPresetAzureAD         = "azure_ad"
+	claimMappingPresetOkta            = "okta"
+	claimMappingPresetGoogle          = "google"
+)
+
+// claimMapping is a single claim_mappings block.
+type claimMapping struct {
+	AttributeName string
+	ClaimName     string
+	List          bool
+	Encrypt       bool
+}
+
+// claimMappingPresets is the standard claim mappings per preset and identity provider type.
+var claimMappingPresets = map[string]map[string][]claimMapping{
+	claimMappingPresetActiveDirectory: {
+		identityProviderLdap:            activeDirectoryClaimMappings,
+		identityProviderLdapCertificate: activeDirectoryClaimMappings,
+	},
+	claimMappingPresetAzureAD: {
+		identityProviderSaml: {
+			{AttributeName: "http://schemas.microsoft.com/identity/claims/objectidentifier", ClaimName: "userId"},
+			{AttributeName: "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name", ClaimName: "username"},
+			{AttributeName: "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/givenname", ClaimName: "firstName"},
+			{AttributeName: "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/surname", ClaimName: "lastName"},
+			{AttributeName: "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", ClaimName: "emails", List: true},
+			{AttributeName: "http://schemas.microsoft.com/ws/2008/06/identity/claims/groups", ClaimName: "groups", List: true},
+		},
+		identityProviderOidc: {
+			{AttributeName: "oid", ClaimName: "userId"},
+			{AttributeName: "preferred_username", ClaimName: "username"},
+			{AttributeName: "given_name", ClaimName: "firstName"},
+			{AttributeName: "family_name", ClaimName: "lastName"},
+			{AttributeName: "email", ClaimName: "emails", List: true},
+			{AttributeName: "groups", ClaimName: "groups", List: true},
+		},
+	},
+	claimMappingPresetOkta: {
+		identityProviderSaml: {
+			{AttributeName: "login", ClaimName: "username"},
+			{AttributeName: "firstName", ClaimName: "firstName"},
+			{AttributeName: "lastName", ClaimName: "lastName"},
+			{AttributeName: "email", ClaimName: "emails", List: true},
+			{AttributeName: "groups", ClaimName: "groups", List: true},
+		},
+		identityProviderOidc: {
+			{AttributeName: "sub", ClaimName: "userId"},
+			{AttributeName: "preferred_username", ClaimName: "username"},
+			{AttributeName: "given_name", ClaimName: "firstName"},
+			{AttributeName: "family_name", ClaimName: "lastName"},
+			{AttributeName: "email", ClaimName: "emails", List: true},
+			{AttributeName: "groups", ClaimName: "groups", List: true},
+		},
+	},
+	claimMappingPresetGoogle: {
+		identityProviderOidc: {
+			{AttributeName: "sub", ClaimName: "userId"},
+			{AttributeName: "email", ClaimName: "username"},
+			{AttributeName: "given_name", ClaimName: "firstName"},
+			{AttributeName: "family_name", ClaimName: "lastName"},
+			{AttributeName: "email", ClaimName: "emails", List: true},
+			{AttributeName: "hd", ClaimName: "domain"},
+		},
+	},
+}
+
+var activeDirectoryClaimMappings = []claimMapping{
+	{AttributeName: "objectGUID", ClaimName: "userId"},
+	{AttributeName: "sAMAccountName", ClaimName: "username"},
+	{AttributeName: "givenName", ClaimName: "firstName"},
+	{AttributeName: "sn", ClaimName: "lastName"},
+	{AttributeName: "mail", ClaimName: "emails", List: true},
+	{AttributeName: "memberOf", ClaimName: "groups", List: true},
+}
+
+// ldapAttributeNameRegex matches an LDAP attribute description, a name or OID with optional options, RFC 4512.
+var ldapAttributeNameRegex = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|[0-9]+(?:\.[0-9]+)+)(?:;[A-Za-z0-9-]+)*$`)
+
+func claimMappingPresetNames() []string {
+	names := make([]string, 0, len(claimMappingPresets))
+	for name := range claimMappingPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func claimMappingPresetSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"claim_mapping_preset": {
+			Type:         schema.TypeString,
+			Description:  "Standard claim mappings for a well-known directory, claim_mappings with the same claim_name override the preset.",
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(claimMappingPresetNames(), false),
+		},
+	}
+}
+
+// identityProviderClaimMappingsCustomizeDiff expands claim_mapping_preset into claim_mappings,
+// and validates the claim mappings for the identity provider type.
+func identityProviderClaimMappingsCustomizeDiff(providerType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		raw := d.GetRawConfig()
+		if raw.IsNull() || !raw.IsKnown() {
+			return nil
+		}
+		configured, known := claimMappingsFromRawConfig(raw.GetAttr("claim_mappings"))
+		if !known {
+			return nil
+		}
+		var errs []error
+		if err := validateClaimMappings(providerType, configured); err != nil {
+			errs = append(errs, err)
+		}
+		if v, ok := d.GetOk("on_demand_claim_mappings"); ok {
+			if err := validateOnDemandClaimNames(v.(*schema.Set).List()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+
+		if !d.NewValueKnown("claim_mapping_preset") {
+			return nil
+		}
+		preset, ok := d.GetOk("claim_mapping_preset")
+		if !ok {
+			// claim_mappings is computed, so the preset mappings stay in the state unless they are
+			// replaced by the configured claim_mappings when the preset is removed.
+			if old, _ := d.GetChange("claim_mapping_preset"); old.(string) == "" {
+				return nil
+			}
+			return setClaimMappings(d, configured)
+		}
+		mappings, ok := claimMappingPresets[preset.(string)][providerType]
+		if !ok {
+			return fmt.Errorf("claim_mapping_preset %s is not available for %s identity providers", preset, providerType)
+		}
+		return setClaimMappings(d, mergeClaimMappings(mappings, configured))
+	}
+}
+
+// setClaimMappings sets the planned claim_mappings to mappings, if they differ from the current claim_mappings.
+func setClaimMappings(d *schema.ResourceDiff, mappings []claimMapping) error {
+	out := make([]interface{}, 0, len(mappings))
+	for _, c := range mappings {
+		out = append(out, map[string]interface{}{
+			"attribute_name": c.AttributeName,
+			"claim_name":     c.ClaimName,
+			"list":           c.List,
+			"encrypt":        c.Encrypt,
+		})
+	}
+	if current, ok := d.Get("claim_mappings").(*schema.Set); ok && current.Equal(schema.NewSet(resourceIdentityProviderClaimMappingsHash, out)) {
+		return nil
+	}
+	return d.SetNew("claim_mappings", out)
+}
+
+// claimMappingsFromRawConfig reads claim_mappings from the raw configuration,
+// the planned value can not be used since claim_mappings is computed.
+func claimMappingsFromRawConfig(v cty.Value) ([]claimMapping, bool) {
+	if v.IsNull() {
+		return nil, true
+	}
+	if !v.IsWhollyKnown() {
+		return nil, false
+	}
+	mappings := make([]claimMapping, 0, v.LengthInt())
+	for it := v.ElementIterator(); it.Next(); {
+		_, block := it.Element()
+		c := claimMapping{}
+		if s := block.GetAttr("attribute_name"); !s.IsNull() {
+			c.AttributeName = s.AsString()
+		}
+		if s := block.GetAttr("claim_name"); !s.IsNull() {
+			c.ClaimName = s.AsString()
+		}
+		if b := block.GetAttr("list"); !b.IsNull() {
+			c.List = b.True()
+		}
+		if b := block.GetAttr("encrypt"); !b.IsNull() {
+			c.Encrypt = b.True()
+		}
+		mappings = append(mappings, c)
+	}
+	return mappings, true
+}
+
+// mergeClaimMappings returns the preset with the overrides applied,
+// an override replaces the preset mapping with the same claim name.
+func mergeClaimMappings(preset, overrides []claimMapping) []claimMapping {
+	overridden := make(map[string]bool, len(overrides))
+	for _, c := range overrides {
+		overridden[c.ClaimName] = true
+	}
+	merged := make([]claimMapping, 0, len(preset)+len(overrides))
+	for _, c := range preset {
+		if !overridden[c.ClaimName] {
+			merged = append(merged, c)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// validateClaimMappings checks for duplicate claim names and attribute names that are invalid for the provider type.
+func validateClaimMappings(providerType string, mappings []claimMapping) error {
+	var errs []error
+	seen := make(map[string]string, len(mappings))
+	for _, c := range mappings {
+		if attribute, ok := seen[c.ClaimName]; ok {
+			errs = append(errs, fmt.Errorf("claim_mappings: claim_name %q is mapped from both %q and %q", c.ClaimName, attribute, c.AttributeName))
+		}
+		seen[c.ClaimName] = c.AttributeName
+
+		switch {
+		case len(strings.TrimSpace(c.AttributeName)) == 0:
+			errs = append(errs, fmt.Errorf("claim_mappings: attribute_name for claim %q is empty", c.ClaimName))
+		case c.AttributeName != strings.TrimSpace(c.AttributeName):
+			errs = append(errs, fmt.Errorf("claim_mappings: attribute_name %q has leading or trailing whitespace", c.AttributeName))
+		case (providerType == identityProviderLdap || providerType == identityProviderLdapCertificate) && !ldapAttributeNameRegex.MatchString(c.AttributeName):
+			errs = append(errs, fmt.Errorf("claim_mappings: attribute_name %q is not a valid LDAP attribute name", c.AttributeName))
+		case providerType == identityProviderOidc && strings.ContainsAny(c.AttributeName, " \t\n"):
+			errs = append(errs, fmt.Errorf("claim_mappings: attribute_name %q is not a valid OIDC claim name", c.AttributeName))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateOnDemandClaimNames checks for duplicate claim names per platform in on_demand_claim_mappings.
+func validateOnDemandClaimNames(mappings []interface{}) error {
+	var errs []error
+	seen := make(map[string]bool, len(mappings))
+	for _, raw := range mappings {
+		m := raw.(map[string]interface{})
+		key := fmt.Sprintf("%s/%s", m["platform"], m["claim_name"])
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("on_demand_claim_mappings: claim_name %q is defined more than once for platform %s", m["claim_name"], m["platform"]))
+		}
+		seen[key] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package appgate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestClaimMappingPresetsAreValid(t *testing.T) {
+	for preset, types := range claimMappingPresets {
+		for providerType, mappings := range types {
+			if err := validateClaimMappings(providerType, mappings); err != nil {
+				t.Errorf("preset %s for %s: %s", preset, providerType, err)
+			}
+		}
+	}
+}
+
+func TestMergeClaimMappings(t *testing.T) {
+	preset := claimMappingPresets[claimMappingPresetActiveDirectory][identityProviderLdap]
+	got := mergeClaimMappings(preset, []claimMapping{
+		{AttributeName: "userPrincipalName", ClaimName: "username"},
+		{AttributeName: "department", ClaimName: "department"},
+	})
+	if len(got) != len(preset)+1 {
+		t.Fatalf("got %d claim mappings, want %d", len(got), len(preset)+1)
+	}
+	for _, c := range got {
+		if c.ClaimName == "username" && c.AttributeName != "userPrincipalName" {
+			t.Errorf("username is mapped from %s, want the override userPrincipalName", c.AttributeName)
+		}
+	}
+	if err := validateClaimMappings(identityProviderLdap, got); err != nil {
+		t.Errorf("merged claim mappings are invalid: %s", err)
+	}
+}
+
+func TestValidateClaimMappings(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerType string
+		mappings     []claimMapping
+		wantErr      string
+	}{
+		{
+			name:         "ldap attribute with option",
+			providerType: identityProviderLdap,
+			mappings:     []claimMapping{{AttributeName: "cn;lang-en", ClaimName: "name"}},
+		},
+		{
+			name:         "ldap oid",
+			providerType: identityProviderLdap,
+			mappings:     []claimMapping{{AttributeName: "2.5.4.3", ClaimName: "name"}},
+		},
+		{
+			name:         "ldap invalid attribute",
+			providerType: identityProviderLdap,
+			mappings:     []claimMapping{{AttributeName: "given name", ClaimName: "firstName"}},
+			wantErr:      "not a valid LDAP attribute name",
+		},
+		{
+			name:         "saml uri",
+			providerType: identityProviderSaml,
+			mappings:     []claimMapping{{AttributeName: "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn", ClaimName: "username"}},
+		},
+		{
+			name:         "oidc whitespace",
+			providerType: identityProviderOidc,
+			mappings:     []claimMapping{{AttributeName: "given name", ClaimName: "firstName"}},
+			wantErr:      "not a valid OIDC claim name",
+		},
+		{
+			name:         "empty attribute",
+			providerType: identityProviderRadius,
+			mappings:     []claimMapping{{AttributeName: " ", ClaimName: "username"}},
+			wantErr:      "is empty",
+		},
+		{
+			name:         "duplicate claim name",
+			providerType: identityProviderSaml,
+			mappings: []claimMapping{
+				{AttributeName: "email", ClaimName: "username"},
+				{AttributeName: "login", ClaimName: "username"},
+			},
+			wantErr: `claim_name "username" is mapped from both "email" and "login"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateClaimMappings(tt.providerType, tt.mappings)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOnDemandClaimNames(t *testing.T) {
+	mappings := []interface{}{
+		map[string]interface{}{"claim_name": "antiVirus", "platform": "desktop.windows.all"},
+		map[string]interface{}{"claim_name": "antiVirus", "platform": "desktop.macos.all"},
+	}
+	if err := validateOnDemandClaimNames(mappings); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	mappings = append(mappings, map[string]interface{}{"claim_name": "antiVirus", "platform": "desktop.macos.all"})
+	if err := validateOnDemandClaimNames(mappings); err == nil {
+		t.Fatal("expected error for duplicate claim_name on desktop.macos.all")
+	}
+}
+
+func TestClaimMappingsFromRawConfig(t *testing.T) {
+	block := func(attribute, claim string, list cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"attribute_name": cty.StringVal(attribute),
+			"claim_name":     cty.StringVal(claim),
+			"list":           list,
+			"encrypt":        cty.NullVal(cty.Bool),
+		})
+	}
+	got, known := claimMappingsFromRawConfig(cty.SetVal([]cty.Value{
+		block("mail", "emails", cty.True),
+	}))
+	want := []claimMapping{{AttributeName: "mail", ClaimName: "emails", List: true}}
+	if !known || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v %t, want %v", got, known, want)
+	}
+	if _, known := claimMappingsFromRawConfig(cty.SetVal([]cty.Value{block("mail", "emails", cty.UnknownVal(cty.Bool))})); known {
+		t.Fatal("expected unknown claim_mappings")
+	}
+	if got, known := claimMappingsFromRawConfig(cty.NullVal(cty.Set(cty.DynamicPseudoType))); !known || got != nil {
+		t.Fatalf("got %v %t for null claim_mappings", got, known)
+	}
+}
@@ -0,0 +1,173 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// identityProviderCustomizeDiff is the CustomizeDiff shared by all identity provider resources.
+func identityProviderCustomizeDiff(providerType string) schema.CustomizeDiffFunc {
+	return customdiff.All(
+		identityProviderClaimMappingsCustomizeDiff(providerType),
+		identityProviderReferencesCustomizeDiff,
+	)
+}
+
+// identityProviderReferencesCustomizeDiff resolves ip_pool_v4, ip_pool_v6, user_scripts and
+// on_boarding_two_factor.mfa_provider_id from a name or ID to the ID, and verifies that each
+// reference exists and has the right type, so a typo fails the plan instead of the apply.
+// The attributes are computed to store the resolved ID, so a removed attribute is cleared here.
+func identityProviderReferencesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, k := range []string{"ip_pool_v4", "ip_pool_v6"} {
+		if err := resolveIdentityProviderIPPool(ctx, d, meta, token, k, raw); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := resolveIdentityProviderUserScripts(ctx, d, meta, token, raw); err != nil {
+		errs = append(errs, err)
+	}
+	if err := resolveIdentityProviderOnBoardingMfaProvider(ctx, d, meta, token, raw); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func resolveIdentityProviderIPPool(ctx context.Context, d *schema.ResourceDiff, meta interface{}, token, key string, raw cty.Value) error {
+	if !raw.Type().HasAttribute(key) {
+		return nil
+	}
+	v := raw.GetAttr(key)
+	if !v.IsKnown() {
+		return nil
+	}
+	if v.IsNull() {
+		if len(d.Get(key).(string)) > 0 {
+			return d.SetNew(key, "")
+		}
+		return nil
+	}
+	api := meta.(*Client).API.IPPoolsApi
+	reference := v.AsString()
+	find := findIpPoolByName
+	if isUUID(reference) {
+		find = findIpPoolByUUID
+	}
+	pool, diags := find(ctx, api, reference, token)
+	if diags.HasError() {
+		return fmt.Errorf("%s: IP pool %q does not exist, %w", key, reference, diagnosticsError(diags))
+	}
+	if ipv6 := key == "ip_pool_v6"; pool.GetIpVersion6() != ipv6 {
+		return fmt.Errorf("%s: IP pool %q is an IPv%s pool", key, pool.GetName(), map[bool]string{true: "6", false: "4"}[pool.GetIpVersion6()])
+	}
+	if d.Get(key).(string) != pool.GetId() {
+		return d.SetNew(key, pool.GetId())
+	}
+	return nil
+}
+
+func resolveIdentityProviderUserScripts(ctx context.Context, d *schema.ResourceDiff, meta interface{}, token string, raw cty.Value) error {
+	if !raw.Type().HasAttribute("user_scripts") {
+		return nil
+	}
+	v := raw.GetAttr("user_scripts")
+	if !v.IsWhollyKnown() {
+		return nil
+	}
+	current := d.Get("user_scripts").([]interface{})
+	if v.IsNull() {
+		if len(current) > 0 {
+			return d.SetNew("user_scripts", []interface{}{})
+		}
+		return nil
+	}
+	api := meta.(*Client).API.UserClaimScriptsApi
+	var errs []error
+	ids := make([]interface{}, 0, v.LengthInt())
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		if e.IsNull() {
+			continue
+		}
+		reference := e.AsString()
+		find := findUserScriptByName
+		if isUUID(reference) {
+			find = findUserScriptByUUID
+		}
+		script, diags := find(ctx, api, reference, token)
+		if diags.HasError() {
+			errs = append(errs, fmt.Errorf("user_scripts: user claim script %q does not exist, %w", reference, diagnosticsError(diags)))
+			continue
+		}
+		ids = append(ids, script.GetId())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if !attributeEqual(current, ids) {
+		return d.SetNew("user_scripts", ids)
+	}
+	return nil
+}
+
+func resolveIdentityProviderOnBoardingMfaProvider(ctx context.Context, d *schema.ResourceDiff, meta interface{}, token string, raw cty.Value) error {
+	if !raw.Type().HasAttribute("on_boarding_two_factor") {
+		return nil
+	}
+	v := raw.GetAttr("on_boarding_two_factor")
+	if !v.IsKnown() {
+		return nil
+	}
+	current := d.Get("on_boarding_two_factor").([]interface{})
+	if v.IsNull() || v.LengthInt() == 0 {
+		if len(current) > 0 {
+			return d.SetNew("on_boarding_two_factor", []interface{}{})
+		}
+		return nil
+	}
+	id := v.Index(cty.NumberIntVal(0)).GetAttr("mfa_provider_id")
+	if !id.IsKnown() || id.IsNull() || len(current) == 0 || current[0] == nil {
+		return nil
+	}
+	reference := id.AsString()
+	find := findMfaProviderByName
+	if isUUID(reference) {
+		find = findMfaProviderByUUID
+	}
+	provider, diags := find(ctx, meta.(*Client).API.MFAProvidersApi, reference, token)
+	if diags.HasError() {
+		return fmt.Errorf("on_boarding_two_factor: MFA provider %q does not exist, %w", reference, diagnosticsError(diags))
+	}
+	block := current[0].(map[string]interface{})
+	if block["mfa_provider_id"] != provider.GetId() {
+		block["mfa_provider_id"] = provider.GetId()
+		return d.SetNew("on_boarding_two_factor", []interface{}{block})
+	}
+	return nil
+}
+
+// diagnosticsError returns the error diagnostics as an error.
+func diagnosticsError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			messages = append(messages, d.Summary)
+		}
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
@@ -0,0 +1,228 @@
+package appgate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// identityProviderTestSchema returns test_on_apply and test_result.
+// If credentials is true, the test signs in with username and password and returns the user attributes and claims,
+// otherwise the controller only tests the connection to the identity provider, which is the case for SAML since
+// the sign in happens in the browser.
+func identityProviderTestSchema(credentials bool) map[string]*schema.Schema {
+	test := map[string]*schema.Schema{
+		"fail_on_error": {
+			Type:        schema.TypeBool,
+			Description: "Fail the apply if the test fails, otherwise the result is only logged and stored in test_result. A new identity provider is deleted again if the test fails.",
+			Optional:    true,
+			Default:     true,
+		},
+	}
+	if credentials {
+		test["username"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Username of the test user.",
+			Required:    true,
+		}
+		test["password_wo"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Password of the test user. Write-only, the value is not stored in the state. Requires Terraform 1.11 or later.",
+			Optional:    true,
+			Sensitive:   true,
+			WriteOnly:   true,
+		}
+	}
+	return map[string]*schema.Schema{
+		"test_on_apply": {
+			Type:        schema.TypeList,
+			Description: "Test the identity provider after it is created or updated.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        &schema.Resource{Schema: test},
+		},
+		"test_result": {
+			Type:        schema.TypeList,
+			Description: "Result of the last test_on_apply.",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"success": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"message": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"attributes": {
+						Type:        schema.TypeMap,
+						Description: "Raw attributes returned by the identity provider for the test user.",
+						Computed:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"claims": {
+						Type:        schema.TypeMap,
+						Description: "Claims of the test user after the claim mappings are applied.",
+						Computed:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"tested_at": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// identityProviderTestResult is the outcome of test_on_apply.
+type identityProviderTestResult struct {
+	Success    bool
+	Message    string
+	Attributes map[string]string
+	Claims     map[string]string
+}
+
+// testIdentityProviderOnApply runs test_on_apply, if configured, against the identity provider in d.
+// provider is the identity provider as it was sent to the controller, it is used for the connection test
+// when test_on_apply has no username. If created is true and the test fails with fail_on_error, the
+// identity provider is deleted, so the failed create is not left in the state as a tainted resource.
+func testIdentityProviderOnApply(d *schema.ResourceData, meta interface{}, provider interface{}, created bool) error {
+	v, ok := d.GetOk("test_on_apply")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		d.Set("test_result", nil)
+		return nil
+	}
+	cfg := v.([]interface{})[0].(map[string]interface{})
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	ctx := BaseAuthContext(token)
+
+	var result *identityProviderTestResult
+	if username, ok := cfg["username"].(string); ok && len(username) > 0 {
+		password, _ := writeOnlyFromConfigAt(d, cty.GetAttrPath("test_on_apply").IndexInt(0).GetAttr("password_wo"))
+		result, err = testIdentityProviderUser(ctx, meta, d.Id(), username, password)
+	} else {
+		result, err = testIdentityProviderConnection(ctx, meta, provider)
+	}
+	if err != nil {
+		return err
+	}
+	d.Set("test_result", []interface{}{
+		map[string]interface{}{
+			"success":    result.Success,
+			"message":    result.Message,
+			"attributes": result.Attributes,
+			"claims":     result.Claims,
+			"tested_at":  time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if result.Success {
+		log.Printf("[DEBUG] Identity provider %s test succeeded: %s, attributes %v", d.Get("name"), result.Message, identityProviderTestAttributeNames(result.Attributes))
+		return nil
+	}
+	if cfg["fail_on_error"].(bool) {
+		if created {
+			if err := identityProviderDelete(d, meta); err != nil {
+				return fmt.Errorf("Identity provider %s test_on_apply failed: %s, and the identity provider could not be deleted: %w", d.Get("name"), result.Message, err)
+			}
+			return fmt.Errorf("Identity provider %s test_on_apply failed, the identity provider was not created: %s", d.Get("name"), result.Message)
+		}
+		return fmt.Errorf("Identity provider %s was saved, but test_on_apply failed: %s", d.Get("name"), result.Message)
+	}
+	log.Printf("[WARN] Identity provider %s test failed: %s", d.Get("name"), result.Message)
+	return nil
+}
+
+// testIdentityProviderUser signs in to the identity provider with username and password,
+// and returns the attributes and the claims the controller computes for the user.
+func testIdentityProviderUser(ctx context.Context, meta interface{}, id, username, password string) (*identityProviderTestResult, error) {
+	api := meta.(*Client).API.IdentityProvidersApi
+	args := openapi.NewIdentityProvidersIdAttributesPostRequest(username)
+	if len(password) > 0 {
+		args.SetPassword(password)
+	}
+	res, response, err := api.IdentityProvidersIdAttributesPost(ctx, id).IdentityProvidersIdAttributesPostRequest(*args).Execute()
+	if err != nil {
+		// The controller responds with 4xx if the identity provider rejects the user or is unreachable,
+		// which is a failed test, not a failed apply.
+		if response != nil && response.StatusCode >= 400 && response.StatusCode < 500 {
+			return &identityProviderTestResult{Message: prettyPrintAPIError(err).Error()}, nil
+		}
+		return nil, fmt.Errorf("Could not test identity provider %s, %w", id, prettyPrintAPIError(err))
+	}
+	return &identityProviderTestResult{
+		Success:    true,
+		Message:    fmt.Sprintf("Signed in as %s", username),
+		Attributes: flattenIdentityProviderTestValues(res.GetAttributes()),
+		Claims:     flattenIdentityProviderTestValues(res.GetClaims()),
+	}, nil
+}
+
+// testIdentityProviderConnection tests the connection from the controller to the identity provider.
+func testIdentityProviderConnection(ctx context.Context, meta interface{}, provider interface{}) (*identityProviderTestResult, error) {
+	api := meta.(*Client).API.IdentityProvidersApi
+	res, response, err := api.IdentityProvidersTestPost(ctx).Body(provider).Execute()
+	if err != nil {
+		if response != nil && response.StatusCode >= 400 && response.StatusCode < 500 {
+			return &identityProviderTestResult{Message: prettyPrintAPIError(err).Error()}, nil
+		}
+		return nil, fmt.Errorf("Could not test identity provider, %w", prettyPrintAPIError(err))
+	}
+	return &identityProviderTestResult{
+		Success: res.GetSuccess(),
+		Message: res.GetMessage(),
+	}, nil
+}
+
+// flattenIdentityProviderTestValues converts attribute and claim values to strings,
+// lists and objects are JSON encoded with sorted keys.
+func flattenIdentityProviderTestValues(in map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		switch value := v.(type) {
+		case nil:
+			out[k] = ""
+		case string:
+			out[k] = value
+		case []interface{}:
+			if len(value) == 1 {
+				if s, ok := value[0].(string); ok {
+					out[k] = s
+					continue
+				}
+			}
+			b, _ := json.Marshal(value)
+			out[k] = string(b)
+		default:
+			b, err := json.Marshal(value)
+			if err != nil {
+				out[k] = fmt.Sprint(value)
+				continue
+			}
+			out[k] = string(b)
+		}
+	}
+	return out
+}
+
+// identityProviderTestAttributeNames returns the sorted attribute names, used in log messages.
+func identityProviderTestAttributeNames(in map[string]string) []string {
+	names := make([]string, 0, len(in))
+	for k := range in {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,33 @@
+package appgate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenIdentityProviderTestValues(t *testing.T) {
+	got := flattenIdentityProviderTestValues(map[string]interface{}{
+		"username": "alice",
+		"mail":     []interface{}{"alice@example.com"},
+		"memberOf": []interface{}{"cn=admins", "cn=developers"},
+		"uid":      float64(1001),
+		"locked":   false,
+		"manager":  nil,
+		"address":  map[string]interface{}{"country": "SE", "city": "Gothenburg"},
+	})
+	want := map[string]string{
+		"username": "alice",
+		"mail":     "alice@example.com",
+		"memberOf": `["cn=admins","cn=developers"]`,
+		"uid":      "1001",
+		"locked":   "false",
+		"manager":  "",
+		"address":  `{"city":"Gothenburg","country":"SE"}`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flattenIdentityProviderTestValues() = %v, want %v", got, want)
+	}
+	if names := identityProviderTestAttributeNames(want); !reflect.DeepEqual(names, []string{"address", "locked", "mail", "manager", "memberOf", "uid", "username"}) {
+		t.Fatalf("identityProviderTestAttributeNames() = %v", names)
+	}
+}
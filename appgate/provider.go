@@ -117,23 +117,11 @@ func Provider() *schema.Provider {
 				Description:  "UUID to distinguish the Client device making the request. It is supposed to be same for every login request from the same server.",
 			},
 			"login_timeout": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("APPGATE_LOGIN_TIMEOUT", "10m"),
-				ValidateFunc: func(v interface{}, name string) (warns []string, errs []error) {
-					s, ok := v.(string)
-					if !ok {
-						errs = append(errs, fmt.Errorf("expected type of %q to be string", name))
-						return
-					}
-
-					if _, err := time.ParseDuration(s); err != nil {
-						errs = append(errs, fmt.Errorf("expected %q to be a valid duration, got %v", name, v))
-					}
-
-					return warns, errs
-				},
-				Description: "Maximum amount of time in seconds to wait for a successful login request to the Controller upon startup.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("APPGATE_LOGIN_TIMEOUT", "10m"),
+				ValidateFunc: validateDuration,
+				Description:  "Maximum amount of time in seconds to wait for a successful login request to the Controller upon startup.",
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
@@ -155,18 +143,39 @@ func Provider() *schema.Provider {
 			"appgatesdp_mfa_provider":            dataSourceAppgateMfaProvider(),
 			"appgatesdp_local_user":              dataSourceAppgateLocalUser(),
 			"appgatesdp_identity_provider":       dataSourceAppgateIdentityProvider(),
+			"appgatesdp_identity_providers":      dataSourceAppgateIdentityProviders(),
 			"appgatesdp_appliance_seed":          dataSourceAppgateApplianceSeed(),
+			"appgatesdp_appliance_status":        dataSourceAppgateApplianceStatus(),
+			"appgatesdp_appliance_statuses":      dataSourceAppgateApplianceStatuses(),
 			"appgatesdp_certificate_authority":   dataSourceAppgateCertificateAuthority(),
 			"appgatesdp_client_profile":          dataSourceClientProfile(),
+			"appgatesdp_references":              dataSourceAppgateReferences(),
+			"appgatesdp_token_records":           dataSourceAppgateTokenRecords(),
+			"appgatesdp_blacklisted_users":       dataSourceAppgateBlacklistedUsers(),
+			"appgatesdp_registered_devices":      dataSourceAppgateRegisteredDevices(),
+			"appgatesdp_otp_seeds":               dataSourceAppgateOtpSeeds(),
 		},
-		ResourcesMap: map[string]*schema.Resource{
+		ResourcesMap: withUpdatedCustomizeDiff(map[string]*schema.Resource{
 			"appgatesdp_appliance":                          resourceAppgateAppliance(),
 			"appgatesdp_appliance_controller_activation":    resourceAppgateApplianceControllerActivation(),
+			"appgatesdp_appliance_upgrade":                  resourceAppgateApplianceUpgrade(),
+			"appgatesdp_appliance_gateway":                  resourceAppgateApplianceGateway(),
+			"appgatesdp_appliance_log_forwarder":            resourceAppgateApplianceLogForwarder(),
+			"appgatesdp_appliance_portal":                   resourceAppgateAppliancePortal(),
+			"appgatesdp_appliance_networking":               resourceAppgateApplianceNetworking(),
+			"appgatesdp_appliance_backup":                   resourceAppgateApplianceBackup(),
+			"appgatesdp_appliance_maintenance":              resourceAppgateApplianceMaintenance(),
+			"appgatesdp_appliance_reboot":                   resourceAppgateApplianceReboot(),
 			"appgatesdp_entitlement":                        resourceAppgateEntitlement(),
+			"appgatesdp_entitlement_action":                 resourceAppgateEntitlementAction(),
 			"appgatesdp_site":                               resourceAppgateSite(),
+			"appgatesdp_site_upgrade":                       resourceAppgateSiteUpgrade(),
 			"appgatesdp_ringfence_rule":                     resourceAppgateRingfenceRule(),
 			"appgatesdp_condition":                          resourceAppgateCondition(),
 			"appgatesdp_policy":                             resourceAppgatePolicy(),
+			"appgatesdp_policy_entitlement_attachment":      resourceAppgatePolicyEntitlementAttachment(),
+			"appgatesdp_policy_ringfence_rule_attachment":   resourceAppgatePolicyRingfenceRuleAttachment(),
+			"appgatesdp_policy_admin_role_attachment":       resourceAppgatePolicyAdminRoleAttachment(),
 			"appgatesdp_device_policy":                      resourceAppgateDevicePolicy(),
 			"appgatesdp_dns_policy":                         resourceAppgateDnsPolicy(),
 			"appgatesdp_access_policy":                      resourceAppgateAccessPolicy(),
@@ -183,9 +192,13 @@ func Provider() *schema.Provider {
 			"appgatesdp_trusted_certificate":                resourceAppgateTrustedCertificate(),
 			"appgatesdp_mfa_provider":                       resourceAppgateMfaProvider(),
 			"appgatesdp_local_user":                         resourceAppgateLocalUser(),
+			"appgatesdp_local_users":                        resourceAppgateLocalUsers(),
 			"appgatesdp_license":                            resourceAppgateLicense(),
 			"appgatesdp_admin_mfa_settings":                 resourceAdminMfaSettings(),
 			"appgatesdp_blacklist_user":                     resourceAppgateBlacklistUser(),
+			"appgatesdp_token_revocation":                   resourceAppgateTokenRevocation(),
+			"appgatesdp_registered_device_removal":          resourceAppgateRegisteredDeviceRemoval(),
+			"appgatesdp_otp_seed_reset":                     resourceAppgateOtpSeedReset(),
 			"appgatesdp_radius_identity_provider":           resourceAppgateRadiusProvider(),
 			"appgatesdp_oidc_identity_provider":             resourceAppgateOidcProvider(),
 			"appgatesdp_saml_identity_provider":             resourceAppgateSamlProvider(),
@@ -194,7 +207,7 @@ func Provider() *schema.Provider {
 			"appgatesdp_connector_identity_provider":        resourceAppgateConnectorProvider(),
 			"appgatesdp_client_profile":                     resourceAppgateClientProfile(),
 			"appgatesdp_stop_policy":                        resourceAppgateStopPolicy(),
-		},
+		}),
 	}
 
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
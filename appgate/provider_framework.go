@@ -0,0 +1,140 @@
+package appgate
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServerFactory returns the provider server that combines the plugin SDK provider with
+// the plugin framework provider, which serves the ephemeral resources the SDK does not support.
+func ProviderServerFactory(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	sdkProvider := Provider()
+	providers := []func() tfprotov5.ProviderServer{
+		// the SDK provider is configured first, the framework provider uses its client.
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(sdkProvider)),
+	}
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider serves the ephemeral resources. It has the same configuration as the SDK
+// provider, and shares the *Client configured by the SDK provider.
+type frameworkProvider struct {
+	sdkProvider *schema.Provider
+}
+
+var (
+	_ provider.Provider                       = &frameworkProvider{}
+	_ provider.ProviderWithEphemeralResources = &frameworkProvider{}
+)
+
+func newFrameworkProvider(sdkProvider *schema.Provider) provider.Provider {
+	return &frameworkProvider{sdkProvider: sdkProvider}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "appgatesdp"
+}
+
+// Schema must be identical to the schema of the SDK provider, which is verified by the mux server.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = providerschema.Schema{
+		Attributes: map[string]providerschema.Attribute{
+			"url": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"username": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"password": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"provider": providerschema.StringAttribute{
+				Optional: true,
+			},
+			"insecure": providerschema.BoolAttribute{
+				Optional: true,
+			},
+			"debug": providerschema.BoolAttribute{
+				Optional: true,
+			},
+			"client_version": providerschema.Int64Attribute{
+				Optional: true,
+			},
+			"config_path": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the appgate config file. Can be set with APPGATE_CONFIG_PATH.",
+			},
+			"pem_filepath": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the controller's CA cert file in PEM format",
+			},
+			"bearer_token": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "The Token from the LoginResponse, provided from outside terraform.",
+			},
+			"device_id": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "UUID to distinguish the Client device making the request. It is supposed to be same for every login request from the same server.",
+			},
+			"login_timeout": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum amount of time in seconds to wait for a successful login request to the Controller upon startup.",
+			},
+		},
+	}
+}
+
+// Configure passes the *Client of the SDK provider to the ephemeral resources. The configuration
+// is validated and used by the SDK provider, which is configured before this provider.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	if client, ok := p.sdkProvider.Meta().(*Client); ok {
+		resp.EphemeralResourceData = client
+	}
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newEphemeralApplianceSeed,
+		newEphemeralLocalUserPassword,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+// ephemeralClient is embedded in the ephemeral resources to receive the *Client from the provider.
+type ephemeralClient struct {
+	client *Client
+}
+
+func (e *ephemeralClient) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// ProviderData is nil until the provider is configured.
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", "Expected *Client, this is a bug in the provider.")
+		return
+	}
+	e.client = client
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -30,6 +31,18 @@ var testAccProvider *schema.Provider
 // for tests requiring special provider configurations.
 var testAccProviderFactories map[string]func() (*schema.Provider, error)
 
+// testAccProtoV5ProviderFactories serves the SDK provider together with the framework provider,
+// required by tests of ephemeral resources.
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"appgatesdp": func() (tfprotov5.ProviderServer, error) {
+		factory, err := ProviderServerFactory(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return factory(), nil
+	},
+}
+
 func init() {
 	testAccProvider = Provider()
 	testAccProviders = map[string]*schema.Provider{
@@ -61,6 +74,27 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+// TestProviderServerFactory verifies that the SDK and the framework provider have identical schemas,
+// which is required to combine them.
+func TestProviderServerFactory(t *testing.T) {
+	factory, err := ProviderServerFactory(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp, err := factory().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+	for _, name := range []string{"appgatesdp_appliance_seed", "appgatesdp_local_user_password"} {
+		if _, ok := resp.EphemeralResourceSchemas[name]; !ok {
+			t.Errorf("%s is not an ephemeral resource", name)
+		}
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
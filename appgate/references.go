@@ -0,0 +1,254 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// referenceQuery describes the object we are looking for references to.
+// ID matches attributes holding UUIDs and expressions containing the UUID,
+// Name matches expressions that refer to the object by name, such as criteria scripts,
+// and Tag matches tags and tag links, such as policy entitlement_links.
+type referenceQuery struct {
+	ID   string
+	Name string
+	Tag  string
+}
+
+type objectReference struct {
+	Type      string
+	ID        string
+	Name      string
+	Attribute string
+}
+
+func (r objectReference) String() string {
+	return fmt.Sprintf("%s %q (%s) %s", r.Type, r.Name, r.ID, r.Attribute)
+}
+
+func (q referenceQuery) matchID(values ...string) bool {
+	return len(q.ID) > 0 && inArray(q.ID, values)
+}
+
+func (q referenceQuery) matchTag(values ...string) bool {
+	return len(q.Tag) > 0 && inArray(q.Tag, values)
+}
+
+func (q referenceQuery) matchExpression(expression string) bool {
+	if len(expression) == 0 {
+		return false
+	}
+	if len(q.ID) > 0 && strings.Contains(expression, q.ID) {
+		return true
+	}
+	if len(q.Name) > 0 {
+		return regexp.MustCompile(`(^|[^\w])` + regexp.QuoteMeta(q.Name) + `($|[^\w])`).MatchString(expression)
+	}
+	return false
+}
+
+// findReferences lists the entitlements, policies, conditions, ringfence rules, identity providers
+// and the admin MFA settings that reference the object described by q.
+func findReferences(ctx context.Context, meta interface{}, q referenceQuery) ([]objectReference, error) {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	refs := make([]objectReference, 0)
+
+	entitlements, err := listAllPages(func(pageRange string) ([]openapi.Entitlement, error) {
+		list, _, err := api.EntitlementsApi.EntitlementsGet(ctx).OrderBy("name").Range_(pageRange).Execute()
+		return list.GetData(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list entitlements, %w", prettyPrintAPIError(err))
+	}
+	for _, e := range entitlements {
+		ref := func(attribute string) objectReference {
+			return objectReference{Type: "appgatesdp_entitlement", ID: e.GetId(), Name: e.GetName(), Attribute: attribute}
+		}
+		if q.matchID(e.GetConditions()...) {
+			refs = append(refs, ref("conditions"))
+		}
+		if q.matchID(e.GetAppShortcutScripts()...) {
+			refs = append(refs, ref("app_shortcut_scripts"))
+		}
+		if q.matchID(e.GetSite()) {
+			refs = append(refs, ref("site"))
+		}
+		if q.matchTag(e.GetTags()...) {
+			refs = append(refs, ref("tags"))
+		}
+	}
+
+	policies, err := listAllPages(func(pageRange string) ([]openapi.Policy, error) {
+		list, _, err := api.PoliciesApi.PoliciesGet(ctx).OrderBy("name").Range_(pageRange).Execute()
+		return list.GetData(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list policies, %w", prettyPrintAPIError(err))
+	}
+	for _, p := range policies {
+		ref := func(attribute string) objectReference {
+			return objectReference{Type: "appgatesdp_policy", ID: p.GetId(), Name: p.GetName(), Attribute: attribute}
+		}
+		if q.matchID(p.GetEntitlements()...) {
+			refs = append(refs, ref("entitlements"))
+		}
+		if q.matchTag(p.GetEntitlementLinks()...) {
+			refs = append(refs, ref("entitlement_links"))
+		}
+		if q.matchID(p.GetRingfenceRules()...) {
+			refs = append(refs, ref("ringfence_rules"))
+		}
+		if q.matchTag(p.GetRingfenceRuleLinks()...) {
+			refs = append(refs, ref("ringfence_rule_links"))
+		}
+		if q.matchID(p.GetAdministrativeRoles()...) {
+			refs = append(refs, ref("administrative_roles"))
+		}
+		if q.matchExpression(p.GetExpression()) {
+			refs = append(refs, ref("expression"))
+		}
+		if q.matchTag(p.GetTags()...) {
+			refs = append(refs, ref("tags"))
+		}
+	}
+
+	conditions, err := listAllPages(func(pageRange string) ([]openapi.Condition, error) {
+		list, _, err := api.ConditionsApi.ConditionsGet(ctx).OrderBy("name").Range_(pageRange).Execute()
+		return list.GetData(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list conditions, %w", prettyPrintAPIError(err))
+	}
+	for _, c := range conditions {
+		ref := func(attribute string) objectReference {
+			return objectReference{Type: "appgatesdp_condition", ID: c.GetId(), Name: c.GetName(), Attribute: attribute}
+		}
+		for _, method := range c.GetRemedyMethods() {
+			if q.matchID(method.GetProviderId()) {
+				refs = append(refs, ref("remedy_methods.provider_id"))
+				break
+			}
+		}
+		if q.matchExpression(c.GetExpression()) {
+			refs = append(refs, ref("expression"))
+		}
+		if q.matchTag(c.GetTags()...) {
+			refs = append(refs, ref("tags"))
+		}
+	}
+
+	if len(q.Tag) > 0 {
+		rules, err := listAllPages(func(pageRange string) ([]openapi.RingfenceRule, error) {
+			list, _, err := api.RingfenceRulesApi.RingfenceRulesGet(ctx).OrderBy("name").Range_(pageRange).Execute()
+			return list.GetData(), err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list ringfence rules, %w", prettyPrintAPIError(err))
+		}
+		for _, r := range rules {
+			if q.matchTag(r.GetTags()...) {
+				refs = append(refs, objectReference{Type: "appgatesdp_ringfence_rule", ID: r.GetId(), Name: r.GetName(), Attribute: "tags"})
+			}
+		}
+	}
+
+	providers, err := listIdentityProviders(ctx, api.IdentityProvidersApi, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list identity providers, %w", prettyPrintAPIError(err))
+	}
+	for _, p := range providers {
+		id, _ := p["id"].(string)
+		name, _ := p["name"].(string)
+		ref := func(attribute string) objectReference {
+			return objectReference{Type: "appgatesdp_identity_provider", ID: id, Name: name, Attribute: attribute}
+		}
+		for key, attribute := range map[string]string{"ipPoolV4": "ip_pool_v4", "ipPoolV6": "ip_pool_v6"} {
+			if v, ok := p[key].(string); ok && q.matchID(v) {
+				refs = append(refs, ref(attribute))
+			}
+		}
+		if onboarding, ok := p["onBoarding2FA"].(map[string]interface{}); ok {
+			if v, ok := onboarding["mfaProviderId"].(string); ok && q.matchID(v) {
+				refs = append(refs, ref("on_boarding_two_factor.mfa_provider_id"))
+			}
+		}
+		if tags, ok := p["tags"].([]interface{}); ok && len(q.Tag) > 0 {
+			for _, t := range tags {
+				if t == q.Tag {
+					refs = append(refs, ref("tags"))
+					break
+				}
+			}
+		}
+	}
+
+	if len(q.ID) > 0 {
+		settings, _, err := api.MFAForAdminsApi.AdminMfaSettingsGet(ctx).Execute()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read admin MFA settings, %w", prettyPrintAPIError(err))
+		}
+		if q.matchID(settings.GetProviderId()) {
+			refs = append(refs, objectReference{Type: "appgatesdp_admin_mfa_settings", ID: "admin_mfa_settings", Name: "Admin MFA settings", Attribute: "provider_id"})
+		}
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].Name < refs[j].Name
+	})
+	return refs, nil
+}
+
+func preventDestroyIfReferencedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Fail to delete the object while it is referenced by entitlements, policies, conditions or identity providers.",
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// importPreventDestroyIfReferenced sets prevent_destroy_if_referenced to its default,
+// since it is not stored in the Controller.
+func importPreventDestroyIfReferenced(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("prevent_destroy_if_referenced", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// checkReferencesBeforeDelete returns an error listing the objects referencing q
+// if prevent_destroy_if_referenced is enabled.
+func checkReferencesBeforeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, kind string, q referenceQuery) error {
+	if !d.Get("prevent_destroy_if_referenced").(bool) {
+		return nil
+	}
+	log.Printf("[DEBUG] Looking up references to %s %s before delete", kind, d.Id())
+	refs, err := findReferences(ctx, meta, q)
+	if err != nil {
+		return fmt.Errorf("Failed to look up references to %s, %w", kind, err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	blockers := make([]string, 0, len(refs))
+	for _, r := range refs {
+		blockers = append(blockers, "  "+r.String())
+	}
+	return fmt.Errorf(
+		"%s %q is still referenced and prevent_destroy_if_referenced is set, remove the references first:\n%s",
+		kind, d.Get("name").(string), strings.Join(blockers, "\n"),
+	)
+}
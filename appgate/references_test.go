@@ -0,0 +1,44 @@
+package appgate
+
+import "testing"
+
+func TestReferenceQueryMatchExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      referenceQuery
+		expression string
+		want       bool
+	}{
+		{
+			name:       "uuid",
+			query:      referenceQuery{ID: "4c07bc67-57ea-42dd-b702-c2d6c45419fc"},
+			expression: "return claims.user.ag.identityProviderId === '4c07bc67-57ea-42dd-b702-c2d6c45419fc';",
+			want:       true,
+		},
+		{
+			name:       "script name",
+			query:      referenceQuery{ID: "4c07bc67-57ea-42dd-b702-c2d6c45419fc", Name: "office_hours"},
+			expression: "return criteriaScripts.office_hours(claims);",
+			want:       true,
+		},
+		{
+			name:       "script name prefix",
+			query:      referenceQuery{ID: "4c07bc67-57ea-42dd-b702-c2d6c45419fc", Name: "office"},
+			expression: "return criteriaScripts.office_hours(claims);",
+			want:       false,
+		},
+		{
+			name:       "empty expression",
+			query:      referenceQuery{ID: "4c07bc67-57ea-42dd-b702-c2d6c45419fc"},
+			expression: "",
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.matchExpression(tt.expression); got != tt.want {
+				t.Fatalf("matchExpression(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
@@ -55,6 +55,8 @@ func resourceAppgateAdministrativeRole() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"privileges": {
 				Type:     schema.TypeSet,
 				Required: true,
@@ -373,6 +375,7 @@ func resourceAppgateAdministrativeRoleRead(ctx context.Context, d *schema.Resour
 		}
 		return diag.FromErr(fmt.Errorf("Failed to read Administrative role, %w", err))
 	}
+	setUpdated(d, administrativeRole.GetUpdated())
 	d.SetId(administrativeRole.GetId())
 	d.Set("administrative_role_id", administrativeRole.GetId())
 	d.Set("name", administrativeRole.GetName())
@@ -444,6 +447,9 @@ func resourceAppgateAdministrativeRoleUpdate(ctx context.Context, d *schema.Reso
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("Failed to read Administrative role while updating, %w", err))
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateAdministrativeRole(), originalAdministrativeRole.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 	if d.HasChange("name") {
 		originalAdministrativeRole.SetName(d.Get("name").(string))
 	}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -35,7 +37,11 @@ func resourceAppgateAppliance() *schema.Resource {
 		UpdateContext: resourceAppgateApplianceUpdate,
 		DeleteContext: resourceAppgateApplianceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceAppgateApplianceImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
 		SchemaVersion: 1,
@@ -61,6 +67,57 @@ func resourceAppgateAppliance() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
+			"wait_for_state": waitForStateSchema(),
+
+			"drain_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Suspend the gateway before destroy, and wait until the active sessions drop to drain_session_threshold or drain_timeout expires.",
+				Optional:    true,
+				Default:     false,
+			},
+
+			"drain_session_threshold": {
+				Type:         schema.TypeInt,
+				Description:  "Number of active sessions on the gateway that is considered drained.",
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"drain_timeout": {
+				Type:         schema.TypeString,
+				Description:  "Maximum amount of time to wait for the gateway to drain, the appliance is deactivated when it expires.",
+				Optional:     true,
+				Default:      "15m",
+				ValidateFunc: validateDuration,
+			},
+
+			"wipe_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Wipe the appliance configuration when it is deactivated on destroy.",
+				Optional:    true,
+				Default:     true,
+			},
+
+			"deactivate_only": {
+				Type:        schema.TypeBool,
+				Description: "Only deactivate the appliance on destroy, and keep it in the Controller.",
+				Optional:    true,
+				Default:     false,
+			},
+
+			"externally_managed_blocks": {
+				Type:        schema.TypeSet,
+				Description: "Blocks managed by appliance sub-resources, such as appgatesdp_appliance_gateway, which are ignored by this resource after create.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(applianceBlockNames(), false),
+				},
+			},
+
 			"hostname": {
 				Type:        schema.TypeString,
 				Description: "Hostname of the Appliance. It's used by other Appliances to communicate with and identify this Appliances.",
@@ -1553,6 +1610,9 @@ func resourceAppgateApplianceCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	d.SetId(appliance.GetId())
+	if err := waitForApplianceFromConfig(ctx, d, meta, appliance); err != nil {
+		return diag.FromErr(err)
+	}
 
 	resourceAppgateApplianceRead(ctx, d, meta)
 	return diags
@@ -1797,6 +1857,7 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 		}
 		return diag.Errorf("Failed to read Appliance, %s", err)
 	}
+	setUpdated(d, appliance.GetUpdated())
 	d.Set("appliance_id", appliance.GetId())
 	d.Set("name", appliance.GetName())
 	d.Set("tags", appliance.GetTags())
@@ -1828,7 +1889,7 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if v, ok := appliance.GetNetworkingOk(); ok {
+	if v, ok := appliance.GetNetworkingOk(); ok && !applianceBlockManagedElsewhere(d, "networking") {
 		networking, err := flattenApplianceNetworking(*v)
 		if err != nil {
 			return diag.FromErr(err)
@@ -1987,7 +2048,7 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if v, ok := appliance.GetGatewayOk(); ok {
+	if v, ok := appliance.GetGatewayOk(); ok && !applianceBlockManagedElsewhere(d, "gateway") {
 		gateway, err := flatttenApplianceGateway(*v, currentVersion)
 		if err != nil {
 			return diag.FromErr(err)
@@ -1997,7 +2058,7 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if v, ok := appliance.GetLogForwarderOk(); ok {
+	if v, ok := appliance.GetLogForwarderOk(); ok && !applianceBlockManagedElsewhere(d, "log_forwarder") {
 		logforward, err := flatttenApplianceLogForwarder(*v, currentVersion, d)
 		if err != nil {
 			return diag.FromErr(err)
@@ -2042,38 +2103,11 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if v, ok := appliance.GetPortalOk(); ok {
-		portals := make([]map[string]interface{}, 0)
-		portal := make(map[string]interface{})
-		portal["enabled"] = v.GetEnabled()
-		// get local state from the portal attribute, for values that are not included
-		// in the response body
-		var localPortal map[string]interface{}
-		localPortalList := d.Get("portal").([]interface{})
-		for _, l := range localPortalList {
-			localPortal = l.(map[string]interface{})
-		}
-		if len(v.GetProxyP12s()) > 0 {
-			proxyp12s, err := flattenAppliancePortalProxyp12s(localPortal, v.GetProxyP12s())
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			portal["proxy_p12s"] = proxyp12s
-		}
-		https_p12, err := flattenApplianceProxyp12s(localPortal, v.GetHttpsP12())
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		portal["https_p12"] = https_p12
-
-		portal["profiles"] = v.GetProfiles()
-		portal["external_profiles"] = v.GetExternalProfiles()
-		signInCustomization, err := flattenAppliancePortalSignInCustomziation(d, v.GetSignInCustomization())
+	if v, ok := appliance.GetPortalOk(); ok && !applianceBlockManagedElsewhere(d, "portal") {
+		portals, err := flattenAppliancePortal(*v, d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		portal["sign_in_customization"] = signInCustomization
-		portals = append(portals, portal)
 		if err := d.Set("portal", portals); err != nil {
 			return diag.FromErr(err)
 		}
@@ -2087,6 +2121,41 @@ func resourceAppgateApplianceRead(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
+func flattenAppliancePortal(in openapi.Portal, d *schema.ResourceData) ([]map[string]interface{}, error) {
+	portals := make([]map[string]interface{}, 0)
+	portal := make(map[string]interface{})
+	portal["enabled"] = in.GetEnabled()
+	// get local state from the portal attribute, for values that are not included
+	// in the response body
+	var localPortal map[string]interface{}
+	localPortalList := d.Get("portal").([]interface{})
+	for _, l := range localPortalList {
+		localPortal = l.(map[string]interface{})
+	}
+	if len(in.GetProxyP12s()) > 0 {
+		proxyp12s, err := flattenAppliancePortalProxyp12s(localPortal, in.GetProxyP12s())
+		if err != nil {
+			return nil, err
+		}
+		portal["proxy_p12s"] = proxyp12s
+	}
+	https_p12, err := flattenApplianceProxyp12s(localPortal, in.GetHttpsP12())
+	if err != nil {
+		return nil, err
+	}
+	portal["https_p12"] = https_p12
+
+	portal["profiles"] = in.GetProfiles()
+	portal["external_profiles"] = in.GetExternalProfiles()
+	signInCustomization, err := flattenAppliancePortalSignInCustomziation(d, in.GetSignInCustomization())
+	if err != nil {
+		return nil, err
+	}
+	portal["sign_in_customization"] = signInCustomization
+	portals = append(portals, portal)
+	return portals, nil
+}
+
 func flattenAppliancePortalProxyp12s(local map[string]interface{}, p12s []openapi.Portal12) ([]map[string]interface{}, error) {
 	var result []map[string]interface{}
 	for k, p12 := range p12s {
@@ -2687,6 +2756,8 @@ func flattenApplianceNetworking(in openapi.ApplianceAllOfNetworking) ([]map[stri
 func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Updating Appliance: %s", d.Get("name").(string))
 	// var diags diag.Diagnostics
+	resourceLocks.Lock(d.Id())
+	defer resourceLocks.Unlock(d.Id())
 	token, err := meta.(*Client).GetToken()
 	if err != nil {
 		return diag.FromErr(err)
@@ -2699,6 +2770,9 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.Errorf("Failed to read Appliance, %s", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateAppliance(), originalAppliance.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		originalAppliance.SetName(d.Get("name").(string))
@@ -2751,7 +2825,7 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("networking") {
+	if d.HasChange("networking") && !applianceBlockManagedElsewhere(d, "networking") {
 		_, v := d.GetChange("networking")
 		networking, err := readNetworkingFromConfig(v.([]interface{}))
 		if err != nil {
@@ -2839,7 +2913,7 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 		originalAppliance.SetController(ctrl)
 	}
 
-	if d.HasChange("gateway") {
+	if d.HasChange("gateway") && !applianceBlockManagedElsewhere(d, "gateway") {
 		_, v := d.GetChange("gateway")
 		gw, err := readGatewayFromConfig(v.([]interface{}), currentVersion)
 		if err != nil {
@@ -2848,7 +2922,7 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 		originalAppliance.SetGateway(gw)
 	}
 
-	if d.HasChange("log_forwarder") {
+	if d.HasChange("log_forwarder") && !applianceBlockManagedElsewhere(d, "log_forwarder") {
 		_, v := d.GetChange("log_forwarder")
 		lf, err := readLogForwardFromConfig(v.([]interface{}))
 		if err != nil {
@@ -2875,7 +2949,7 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 		originalAppliance.SetConnector(iot)
 	}
 
-	if d.HasChange("portal") {
+	if d.HasChange("portal") && !applianceBlockManagedElsewhere(d, "portal") {
 		_, v := d.GetChange("portal")
 		portal, err := readAppliancePortalFromConfig(d, v.([]interface{}))
 		if err != nil {
@@ -2908,6 +2982,9 @@ func resourceAppgateApplianceUpdate(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.Errorf("Could not update appliance %s", prettyPrintAPIError(err))
 	}
+	if err := waitForApplianceFromConfig(ctx, d, meta, originalAppliance); err != nil {
+		return diag.FromErr(err)
+	}
 	return resourceAppgateApplianceRead(ctx, d, meta)
 }
 
@@ -2928,26 +3005,58 @@ func resourceAppgateApplianceDelete(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.Errorf("Failed to delete Appliance while GET, %s", err)
 	}
+	// Drain
+	if d.Get("drain_on_destroy").(bool) && appliance.GetActivated() {
+		timeout, _ := time.ParseDuration(d.Get("drain_timeout").(string))
+		drained, err := drainAppliance(ctx, meta, appliance, d.Get("drain_session_threshold").(int), timeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !drained {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Appliance %s was not drained, deactivating with active sessions", appliance.GetName()),
+				Detail:   fmt.Sprintf("The gateway still had more than %d active sessions when drain_timeout %s, or the delete timeout, expired.", d.Get("drain_session_threshold").(int), timeout),
+			})
+		}
+	}
 	// Deactivate
 	if ok, _ := appliance.GetActivatedOk(); *ok {
-		log.Printf("[DEBUG] Appliance is active, deactivate and wiping before deleting")
+		wipe := d.Get("wipe_on_destroy").(bool)
+		log.Printf("[DEBUG] Appliance is active, deactivate (wipe %t) before deleting", wipe)
 		deactiveRequest := api.AppliancesIdDeactivatePost(ctx, appliance.GetId())
-		_, _, err = deactiveRequest.Wipe(true).Execute()
+		_, _, err = deactiveRequest.Wipe(wipe).Execute()
 		if err != nil {
-			return diag.Errorf("Failed to delete Appliance while deactivating, %s", err)
+			return append(diags, diag.Errorf("Failed to delete Appliance while deactivating, %s", err)...)
 		}
 	}
+	if d.Get("deactivate_only").(bool) {
+		log.Printf("[DEBUG] Appliance %s deactivated, keep it in the Controller", appliance.GetName())
+		d.SetId("")
+		return diags
+	}
 
 	// Delete
 	deleteRequest := api.AppliancesIdDelete(ctx, appliance.GetId())
 	_, err = deleteRequest.Execute()
 	if err != nil {
-		return diag.Errorf("Failed to delete Appliance, %s", err)
+		return append(diags, diag.Errorf("Failed to delete Appliance, %s", err)...)
 	}
 	d.SetId("")
 	return diags
 }
 
+// resourceAppgateApplianceImport sets the destroy options to their defaults,
+// since they are not stored in the Controller.
+func resourceAppgateApplianceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("drain_on_destroy", false)
+	d.Set("drain_session_threshold", 0)
+	d.Set("drain_timeout", "15m")
+	d.Set("wipe_on_destroy", true)
+	d.Set("deactivate_only", false)
+	return []*schema.ResourceData{d}, nil
+}
+
 func readClientInterfaceFromConfig(cinterfaces []interface{}) (openapi.ApplianceAllOfClientInterface, error) {
 	cinterface := openapi.ApplianceAllOfClientInterface{}
 	for _, r := range cinterfaces {
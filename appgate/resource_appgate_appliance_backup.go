@@ -0,0 +1,229 @@
+package appgate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Backup statuses reported by GET /appliances/{id}/backup/{backupId}/status.
+const (
+	BackupStatusProcessing = "processing"
+	BackupStatusDone       = "done"
+	BackupStatusFailed     = "failed"
+)
+
+func resourceAppgateApplianceBackup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateApplianceBackupCreate,
+		ReadContext:   resourceAppgateApplianceBackupRead,
+		DeleteContext: resourceAppgateApplianceBackupDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"appliance_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the appliance to backup.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"destination": {
+				Type:         schema.TypeString,
+				Description:  "Local path the encrypted backup is downloaded to.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"logs": {
+				Type:        schema.TypeBool,
+				Description: "Include logs in the backup.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"audit": {
+				Type:        schema.TypeBool,
+				Description: "Include audit logs in the backup.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values that take a new backup when they change.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"backup_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Description: "Time the backup was downloaded.",
+				Computed:    true,
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Description: "Size of the backup in bytes.",
+				Computed:    true,
+			},
+			"checksum_sha256": {
+				Type:        schema.TypeString,
+				Description: "sha256 checksum of the downloaded backup, computed locally.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceAppgateApplianceBackupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	applianceID := d.Get("appliance_id").(string)
+	log.Printf("[DEBUG] Creating backup of appliance %s", applianceID)
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.ApplianceBackupApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	args := openapi.NewAppliancesIdBackupPostRequestWithDefaults()
+	args.SetLogs(d.Get("logs").(bool))
+	args.SetAudit(d.Get("audit").(bool))
+	backup, res, err := api.AppliancesIdBackupPost(ctx, applianceID).AppliancesIdBackupPostRequest(*args).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusForbidden {
+			return diag.Errorf("Could not backup appliance %s, make sure backup_api_enabled and backup_passphrase are set in appgatesdp_global_settings: %s", applianceID, prettyPrintAPIError(err))
+		}
+		return diag.Errorf("Could not backup appliance %s, %s", applianceID, prettyPrintAPIError(err))
+	}
+	backupID := backup.GetId()
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = d.Timeout(schema.TimeoutCreate) - durationPadding
+	err = backoff.Retry(func() error {
+		status, _, err := api.AppliancesIdBackupBackupIdStatusGet(ctx, applianceID, backupID).Execute()
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("Failed to get backup status, %w", prettyPrintAPIError(err)))
+		}
+		log.Printf("[DEBUG] Backup %s on appliance %s is %s", backupID, applianceID, status.GetStatus())
+		switch status.GetStatus() {
+		case BackupStatusDone:
+			return nil
+		case BackupStatusFailed:
+			return backoff.Permanent(fmt.Errorf("backup %s failed on appliance %s: %s", backupID, applianceID, status.GetResult()))
+		}
+		return fmt.Errorf("backup %s on appliance %s is %s", backupID, applianceID, status.GetStatus())
+	}, backoff.WithContext(b, ctx))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	file, res, err := api.AppliancesIdBackupBackupIdGet(ctx, applianceID, backupID).Execute()
+	if err != nil {
+		return diag.Errorf("Could not download backup %s, %s", backupID, prettyPrintAPIError(err))
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+	// The controller only reports the size of the backup, as Content-Length. A chunked response
+	// has no Content-Length, a truncated chunked response fails the download instead.
+	expectedSize := int64(-1)
+	if res != nil {
+		expectedSize = res.ContentLength
+	}
+	destination := d.Get("destination").(string)
+	size, sum, err := writeBackupFile(file, destination, expectedSize)
+	if err != nil {
+		return diag.Errorf("Could not download backup %s, %s", backupID, err)
+	}
+
+	d.SetId(backupID)
+	d.Set("backup_id", backupID)
+	d.Set("created", time.Now().UTC().Format(time.RFC3339))
+	d.Set("size", size)
+	d.Set("checksum_sha256", sum)
+	return resourceAppgateApplianceBackupRead(ctx, d, meta)
+}
+
+func resourceAppgateApplianceBackupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The backup only exists locally once it is downloaded, take a new backup if it is missing or modified.
+	destination := d.Get("destination").(string)
+	sum, err := getFileSha256Hash(destination)
+	if err != nil {
+		if os.IsNotExist(err) {
+			d.SetId("")
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  "Backup file is missing",
+				Detail:   fmt.Sprintf("%s does not exist, a new backup of appliance %s is taken on the next apply.", destination, d.Get("appliance_id")),
+			}}
+		}
+		return diag.FromErr(err)
+	}
+	if want := d.Get("checksum_sha256").(string); sum != want {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Backup file was modified",
+			Detail:   fmt.Sprintf("%s checksum is %s, expected %s, a new backup of appliance %s is taken on the next apply.", destination, sum, want, d.Get("appliance_id")),
+		}}
+	}
+	return nil
+}
+
+func resourceAppgateApplianceBackupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The downloaded backup is kept, it is only removed from the state.
+	log.Printf("[DEBUG] Deleting appgatesdp_appliance_backup: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// writeBackupFile writes src to path through a temporary file in the same directory, and returns
+// the size and sha256 checksum of the backup. If expectedSize is not negative, a backup of another
+// size is not written to path.
+func writeBackupFile(src io.Reader, path string, expectedSize int64) (int64, string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return 0, "", fmt.Errorf("Could not write backup to %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), src)
+	if err != nil {
+		tmp.Close()
+		return 0, "", fmt.Errorf("Could not write backup to %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, "", fmt.Errorf("Could not write backup to %s: %w", path, err)
+	}
+	if expectedSize >= 0 && size != expectedSize {
+		return 0, "", fmt.Errorf("backup is %d bytes, the controller reported %d bytes", size, expectedSize)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, "", fmt.Errorf("Could not write backup to %s: %w", path, err)
+	}
+	return size, fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,101 @@
+package appgate
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestWriteBackupFile(t *testing.T) {
+	content := strings.Repeat("encrypted backup ", 1024)
+	path := filepath.Join(t.TempDir(), "appliance.bkp")
+	size, sum, err := writeBackupFile(strings.NewReader(content), path, int64(len(content)))
+	if err != nil {
+		t.Fatalf("writeBackupFile() error = %v", err)
+	}
+	if size != int64(len(content)) {
+		t.Errorf("writeBackupFile() size = %d, want %d", size, len(content))
+	}
+	if want := fmt.Sprintf("%x", sha256.Sum256([]byte(content))); sum != want {
+		t.Errorf("writeBackupFile() checksum = %s, want %s", sum, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("backup mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the backup in the destination directory, got %d files", len(entries))
+	}
+}
+
+func TestWriteBackupFileMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "appliance.bkp")
+	if _, _, err := writeBackupFile(strings.NewReader("backup"), path, -1); err == nil {
+		t.Fatal("expected error when the destination directory does not exist")
+	}
+}
+
+func TestWriteBackupFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "appliance.bkp")
+	if _, _, err := writeBackupFile(strings.NewReader("truncated"), path, 1024); err == nil {
+		t.Fatal("expected error when the backup is smaller than the reported size")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no backup at %s, got %v", path, err)
+	}
+	// chunked responses have no Content-Length
+	size, _, err := writeBackupFile(strings.NewReader("chunked"), path, -1)
+	if err != nil {
+		t.Fatalf("writeBackupFile() error = %v", err)
+	}
+	if size != int64(len("chunked")) {
+		t.Errorf("writeBackupFile() size = %d, want %d", size, len("chunked"))
+	}
+}
+
+func TestAccApplianceBackup(t *testing.T) {
+	applianceID := os.Getenv("APPGATE_BACKUP_APPLIANCE_ID")
+	if len(applianceID) == 0 {
+		t.Skip("APPGATE_BACKUP_APPLIANCE_ID must be set for the appliance backup acceptance test")
+	}
+	resourceName := "appgatesdp_appliance_backup.backup"
+	destination := filepath.Join(t.TempDir(), "controller.bkp")
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "appgatesdp_appliance_backup" "backup" {
+  appliance_id = "%s"
+  destination  = "%s"
+}
+`, applianceID, destination),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "backup_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "created"),
+					resource.TestCheckResourceAttrSet(resourceName, "checksum_sha256"),
+					func(s *terraform.State) error {
+						if _, err := os.Stat(destination); err != nil {
+							return fmt.Errorf("backup was not downloaded: %w", err)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
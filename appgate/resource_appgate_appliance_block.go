@@ -0,0 +1,333 @@
+package appgate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// applianceBlock is a block on the appliance, such as gateway or portal,
+// that can be managed by a sub-resource without managing the rest of the appliance.
+type applianceBlock struct {
+	// name is the name of the block in appgatesdp_appliance and in the sub-resource.
+	name        string
+	description string
+	// get returns the block, to verify that a write was not overwritten by another writer.
+	get     func(a *openapi.Appliance) interface{}
+	flatten func(a *openapi.Appliance, d *schema.ResourceData, currentVersion *version.Version) ([]map[string]interface{}, error)
+	expand  func(a *openapi.Appliance, d *schema.ResourceData, v []interface{}, currentVersion *version.Version) error
+	// disable is called when the sub-resource is destroyed, blocks without disable are left as they are.
+	disable func(a *openapi.Appliance)
+}
+
+func applianceBlocks() []applianceBlock {
+	return []applianceBlock{
+		applianceGatewayBlock(),
+		applianceLogForwarderBlock(),
+		appliancePortalBlock(),
+		applianceNetworkingBlock(),
+	}
+}
+
+func applianceGatewayBlock() applianceBlock {
+	return applianceBlock{
+		name:        "gateway",
+		description: "Gateway settings of an existing appliance.",
+		get: func(a *openapi.Appliance) interface{} {
+			return a.GetGateway()
+		},
+		flatten: func(a *openapi.Appliance, d *schema.ResourceData, currentVersion *version.Version) ([]map[string]interface{}, error) {
+			return flatttenApplianceGateway(a.GetGateway(), currentVersion)
+		},
+		expand: func(a *openapi.Appliance, d *schema.ResourceData, v []interface{}, currentVersion *version.Version) error {
+			gw, err := readGatewayFromConfig(v, currentVersion)
+			if err != nil {
+				return err
+			}
+			a.SetGateway(gw)
+			return nil
+		},
+		disable: func(a *openapi.Appliance) {
+			gw := a.GetGateway()
+			gw.SetEnabled(false)
+			a.SetGateway(gw)
+		},
+	}
+}
+
+func applianceLogForwarderBlock() applianceBlock {
+	return applianceBlock{
+		name:        "log_forwarder",
+		description: "LogForwarder settings of an existing appliance.",
+		get: func(a *openapi.Appliance) interface{} {
+			return a.GetLogForwarder()
+		},
+		flatten: func(a *openapi.Appliance, d *schema.ResourceData, currentVersion *version.Version) ([]map[string]interface{}, error) {
+			return flatttenApplianceLogForwarder(a.GetLogForwarder(), currentVersion, d)
+		},
+		expand: func(a *openapi.Appliance, d *schema.ResourceData, v []interface{}, currentVersion *version.Version) error {
+			lf, err := readLogForwardFromConfig(v)
+			if err != nil {
+				return err
+			}
+			a.SetLogForwarder(lf)
+			return nil
+		},
+		disable: func(a *openapi.Appliance) {
+			lf := a.GetLogForwarder()
+			lf.SetEnabled(false)
+			a.SetLogForwarder(lf)
+		},
+	}
+}
+
+func appliancePortalBlock() applianceBlock {
+	return applianceBlock{
+		name:        "portal",
+		description: "Portal settings of an existing appliance.",
+		get: func(a *openapi.Appliance) interface{} {
+			return a.GetPortal()
+		},
+		flatten: func(a *openapi.Appliance, d *schema.ResourceData, currentVersion *version.Version) ([]map[string]interface{}, error) {
+			return flattenAppliancePortal(a.GetPortal(), d)
+		},
+		expand: func(a *openapi.Appliance, d *schema.ResourceData, v []interface{}, currentVersion *version.Version) error {
+			portal, err := readAppliancePortalFromConfig(d, v)
+			if err != nil {
+				return err
+			}
+			a.SetPortal(portal)
+			return nil
+		},
+		disable: func(a *openapi.Appliance) {
+			portal := a.GetPortal()
+			portal.SetEnabled(false)
+			a.SetPortal(portal)
+		},
+	}
+}
+
+func applianceNetworkingBlock() applianceBlock {
+	return applianceBlock{
+		name:        "networking",
+		description: "Networking settings of an existing appliance. The networking is left as it is when the resource is destroyed.",
+		get: func(a *openapi.Appliance) interface{} {
+			return a.GetNetworking()
+		},
+		flatten: func(a *openapi.Appliance, d *schema.ResourceData, currentVersion *version.Version) ([]map[string]interface{}, error) {
+			return flattenApplianceNetworking(a.GetNetworking())
+		},
+		expand: func(a *openapi.Appliance, d *schema.ResourceData, v []interface{}, currentVersion *version.Version) error {
+			networking, err := readNetworkingFromConfig(v)
+			if err != nil {
+				return err
+			}
+			a.SetNetworking(networking)
+			return nil
+		},
+	}
+}
+
+func applianceBlockNames() []string {
+	names := make([]string, 0)
+	for _, b := range applianceBlocks() {
+		names = append(names, b.name)
+	}
+	return names
+}
+
+// applianceBlockManagedElsewhere returns true if the block is listed in externally_managed_blocks.
+func applianceBlockManagedElsewhere(d *schema.ResourceData, name string) bool {
+	v, ok := d.GetOk("externally_managed_blocks")
+	if !ok {
+		return false
+	}
+	return v.(*schema.Set).Contains(name)
+}
+
+func resourceAppgateApplianceGateway() *schema.Resource {
+	return resourceAppgateApplianceBlock(applianceGatewayBlock())
+}
+
+func resourceAppgateApplianceLogForwarder() *schema.Resource {
+	return resourceAppgateApplianceBlock(applianceLogForwarderBlock())
+}
+
+func resourceAppgateAppliancePortal() *schema.Resource {
+	return resourceAppgateApplianceBlock(appliancePortalBlock())
+}
+
+func resourceAppgateApplianceNetworking() *schema.Resource {
+	return resourceAppgateApplianceBlock(applianceNetworkingBlock())
+}
+
+// resourceAppgateApplianceBlock manages a single block on an existing appliance,
+// with the same schema as the block in appgatesdp_appliance.
+func resourceAppgateApplianceBlock(block applianceBlock) *schema.Resource {
+	attr := *resourceAppgateAppliance().Schema[block.name]
+	attr.Description = block.description
+	attr.Required = true
+	attr.Optional = false
+	attr.Computed = false
+	attr.ConflictsWith = nil
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgateApplianceBlockCreate(ctx, d, meta, block)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgateApplianceBlockRead(ctx, d, meta, block)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgateApplianceBlockUpdate(ctx, d, meta, block)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgateApplianceBlockDelete(ctx, d, meta, block)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"appliance_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the appliance.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			block.name: &attr,
+		},
+	}
+}
+
+// updateAppliance does a read-modify-write of a block on an appliance, see readModifyWrite.
+// The block is verified against the block in the response of the write, which has the defaults
+// set by the Controller.
+func updateAppliance(ctx context.Context, meta interface{}, applianceID string, timeout time.Duration, block applianceBlock, modify func(a *openapi.Appliance) error) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.AppliancesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	read := func() (*openapi.Appliance, error) {
+		appliance, response, err := api.AppliancesIdGet(ctx, applianceID).Execute()
+		if err != nil {
+			if response != nil && response.StatusCode == http.StatusNotFound {
+				return nil, fmt.Errorf("Appliance %s does not exist", applianceID)
+			}
+			return nil, fmt.Errorf("Failed to read Appliance %s, %w", applianceID, prettyPrintAPIError(err))
+		}
+		return appliance, nil
+	}
+	var written *openapi.Appliance
+	write := func(appliance *openapi.Appliance) error {
+		written, _, err = api.AppliancesIdPut(ctx, applianceID).Appliance(*appliance).Execute()
+		if err != nil {
+			return fmt.Errorf("Could not update Appliance %s %w", applianceID, prettyPrintAPIError(err))
+		}
+		return nil
+	}
+	return readModifyWrite(ctx, applianceID, timeout, read, func(appliance *openapi.Appliance) (*openapi.Appliance, bool, error) {
+		return appliance, true, modify(appliance)
+	}, write, func(appliance *openapi.Appliance) bool {
+		return written != nil && applianceBlockEqual(block.get(appliance), block.get(written))
+	})
+}
+
+func applianceBlockEqual(a, b interface{}) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(x, y)
+}
+
+func resourceAppgateApplianceBlockCreate(ctx context.Context, d *schema.ResourceData, meta interface{}, block applianceBlock) diag.Diagnostics {
+	applianceID := d.Get("appliance_id").(string)
+	log.Printf("[DEBUG] Creating appliance %s on %s", block.name, applianceID)
+	currentVersion := meta.(*Client).ApplianceVersion
+	err := updateAppliance(ctx, meta, applianceID, d.Timeout(schema.TimeoutCreate), block, func(a *openapi.Appliance) error {
+		return block.expand(a, d, d.Get(block.name).([]interface{}), currentVersion)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not set %s on Appliance %w", block.name, err))
+	}
+	d.SetId(applianceID)
+	return resourceAppgateApplianceBlockRead(ctx, d, meta, block)
+}
+
+func resourceAppgateApplianceBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}, block applianceBlock) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading appliance %s: %s", block.name, d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	appliance, res, err := api.AppliancesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), d.Id()).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	v, err := block.flatten(appliance, d, meta.(*Client).ApplianceVersion)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("appliance_id", appliance.GetId())
+	if err := d.Set(block.name, v); err != nil {
+		return diag.Errorf("Unable to read %s %s", block.name, err)
+	}
+	return nil
+}
+
+func resourceAppgateApplianceBlockUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, block applianceBlock) diag.Diagnostics {
+	log.Printf("[DEBUG] Updating appliance %s: %s", block.name, d.Id())
+	currentVersion := meta.(*Client).ApplianceVersion
+	err := updateAppliance(ctx, meta, d.Id(), d.Timeout(schema.TimeoutUpdate), block, func(a *openapi.Appliance) error {
+		return block.expand(a, d, d.Get(block.name).([]interface{}), currentVersion)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not update %s on Appliance %w", block.name, err))
+	}
+	return resourceAppgateApplianceBlockRead(ctx, d, meta, block)
+}
+
+func resourceAppgateApplianceBlockDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, block applianceBlock) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting appliance %s: %s", block.name, d.Id())
+	if block.disable != nil {
+		err := updateAppliance(ctx, meta, d.Id(), d.Timeout(schema.TimeoutDelete), block, func(a *openapi.Appliance) error {
+			block.disable(a)
+			return nil
+		})
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("Could not disable %s on Appliance %w", block.name, err))
+		}
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,98 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccApplianceGatewayBlock(t *testing.T) {
+	resourceName := "appgatesdp_appliance_gateway.test_gateway"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	context := map[string]interface{}{
+		"name":     rName,
+		"hostname": fmt.Sprintf("%s.devops", rName),
+		"weight":   100,
+	}
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckApplianceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckApplianceGatewayBlock(context),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckApplianceExists("appgatesdp_appliance.test_appliance"),
+					resource.TestCheckResourceAttrPair(resourceName, "appliance_id", "appgatesdp_appliance.test_appliance", "id"),
+					resource.TestCheckResourceAttr(resourceName, "gateway.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "gateway.0.enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "gateway.0.vpn.0.weight", "100"),
+					resource.TestCheckResourceAttr(resourceName, "gateway.0.vpn.0.allow_destinations.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckApplianceGatewayBlock(map[string]interface{}{
+					"name":     rName,
+					"hostname": context["hostname"],
+					"weight":   50,
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "gateway.0.vpn.0.weight", "50"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckApplianceGatewayBlock(context map[string]interface{}) string {
+	return Nprintf(`
+data "appgatesdp_site" "default_site" {
+  site_name = "Default Site"
+}
+
+resource "appgatesdp_appliance" "test_appliance" {
+  name     = "%{name}"
+  hostname = "%{hostname}"
+  site     = data.appgatesdp_site.default_site.id
+  client_interface {
+    hostname = "%{hostname}"
+  }
+  networking {
+    nics {
+      enabled = true
+      name    = "eth0"
+      ipv4 {
+        dhcp {
+          enabled = true
+          dns     = true
+          routers = true
+          ntp     = true
+        }
+      }
+    }
+  }
+  externally_managed_blocks = ["gateway"]
+}
+
+resource "appgatesdp_appliance_gateway" "test_gateway" {
+  appliance_id = appgatesdp_appliance.test_appliance.id
+  gateway {
+    enabled = true
+    vpn {
+      weight = %{weight}
+      allow_destinations {
+        nic     = "eth0"
+        address = "0.0.0.0"
+        netmask = 0
+      }
+    }
+  }
+}
+`, context)
+}
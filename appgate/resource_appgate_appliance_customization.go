@@ -52,6 +52,8 @@ func resourceAppgateApplianceCustomizations() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"file": {
 				Type:        schema.TypeString,
 				Description: "Path to the appliance customization binary.",
@@ -159,6 +161,7 @@ func resourceAppgateApplianceCustomizationRead(d *schema.ResourceData, meta inte
 		}
 		return fmt.Errorf("Failed to read Appliance customization, %w", err)
 	}
+	setUpdated(d, customization.GetUpdated())
 	d.SetId(customization.GetId())
 	d.Set("appliance_customization_id", customization.GetId())
 	if err := d.Set("name", customization.GetName()); err != nil {
@@ -196,6 +199,9 @@ func resourceAppgateApplianceCustomizationUpdate(d *schema.ResourceData, meta in
 	if err != nil {
 		return fmt.Errorf("Failed to read Appliance customization while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateApplianceCustomizations(), originalApplianceCustomization.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalApplianceCustomization.SetName(d.Get("name").(string))
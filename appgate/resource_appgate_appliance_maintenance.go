@@ -0,0 +1,133 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAppgateApplianceMaintenance() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateApplianceMaintenanceCreate,
+		ReadContext:   resourceAppgateApplianceMaintenanceRead,
+		DeleteContext: resourceAppgateApplianceMaintenanceDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"appliance_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the controller to keep in maintenance mode.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+		},
+	}
+}
+
+// setApplianceMaintenance toggles maintenance mode on a controller, and waits until the controller is ready.
+func setApplianceMaintenance(ctx context.Context, meta interface{}, applianceID string, enabled bool, timeout time.Duration) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	appliance, _, err := meta.(*Client).API.AppliancesApi.AppliancesIdGet(ctx, applianceID).Execute()
+	if err != nil {
+		return fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err))
+	}
+	if ctrl := appliance.GetController(); !ctrl.GetEnabled() {
+		return fmt.Errorf("Appliance %s is not a controller, maintenance mode is only available on controllers", appliance.GetName())
+	}
+	api := meta.(*Client).API.ApplianceMaintenanceApi
+	args := openapi.NewAppliancesIdMaintenancePostRequest(enabled)
+	if _, _, err := api.AppliancesIdMaintenancePost(ctx, applianceID).AppliancesIdMaintenancePostRequest(*args).Execute(); err != nil {
+		return fmt.Errorf("Could not set maintenance mode %t on %s, %w", enabled, appliance.GetName(), prettyPrintAPIError(err))
+	}
+	start := time.Now()
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = timeout
+	err = backoff.Retry(func() error {
+		status, err := getApplianceStatus(ctx, meta, applianceID)
+		if err != nil {
+			return err
+		}
+		if got := applianceInMaintenance(status); got != enabled {
+			return fmt.Errorf("appliance %q maintenance mode is %t, expected %t", applianceID, got, enabled)
+		}
+		return nil
+	}, b)
+	if err != nil {
+		return fmt.Errorf("Maintenance mode on %s never changed to %t: %w", appliance.GetName(), enabled, err)
+	}
+	b = backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = timeout - time.Since(start)
+	return waitForApplianceState(ctx, meta, applianceID, ApplianceStateControllerReady, b)
+}
+
+// applianceInMaintenance returns true if the appliance status reports maintenance mode.
+func applianceInMaintenance(status *openapi.ApplianceWithStatus) bool {
+	return status.GetMaintenance()
+}
+
+func resourceAppgateApplianceMaintenanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	applianceID := d.Get("appliance_id").(string)
+	log.Printf("[DEBUG] Enabling maintenance mode on appliance %s", applianceID)
+	if err := setApplianceMaintenance(ctx, meta, applianceID, true, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(applianceID)
+	return resourceAppgateApplianceMaintenanceRead(ctx, d, meta)
+}
+
+func resourceAppgateApplianceMaintenanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading appgatesdp_appliance_maintenance: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	_, res, err := api.AppliancesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), d.Id()).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	status, err := getApplianceStatus(ctx, meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !applianceInMaintenance(status) {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Maintenance mode was disabled",
+			Detail:   fmt.Sprintf("Maintenance mode was disabled on %s outside of Terraform, it is enabled again on the next apply.", status.GetName()),
+		}}
+	}
+	d.Set("appliance_id", d.Id())
+	return nil
+}
+
+func resourceAppgateApplianceMaintenanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Disabling maintenance mode on appliance %s", d.Id())
+	if err := setApplianceMaintenance(ctx, meta, d.Id(), false, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,33 @@
+package appgate
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccApplianceMaintenance(t *testing.T) {
+	applianceID := os.Getenv("APPGATE_MAINTENANCE_CONTROLLER_ID")
+	if len(applianceID) == 0 {
+		t.Skip("APPGATE_MAINTENANCE_CONTROLLER_ID must be set for the appliance maintenance acceptance test")
+	}
+	resourceName := "appgatesdp_appliance_maintenance.maintenance"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "appgatesdp_appliance_maintenance" "maintenance" {
+  appliance_id = "%s"
+}
+`, applianceID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "appliance_id", applianceID),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,150 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAppgateApplianceReboot() *schema.Resource {
+	return oneShotActionResource(&schema.Resource{
+		CreateContext: resourceAppgateApplianceRebootCreate,
+		ReadContext:   resourceAppgateApplianceRebootRead,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"appliance_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the appliance to reboot.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"switch_partition": {
+				Type:        schema.TypeBool,
+				Description: "Switch to the inactive partition and reboot, instead of a regular reboot.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Description: "State of the appliance after the reboot.",
+				Computed:    true,
+			},
+		},
+	}, "reboot the appliance again")
+}
+
+func resourceAppgateApplianceRebootCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	applianceID := d.Get("appliance_id").(string)
+	switchPartition := d.Get("switch_partition").(bool)
+	log.Printf("[DEBUG] Rebooting appliance %s, switch partition %t", applianceID, switchPartition)
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	appliance, _, err := api.AppliancesIdGet(ctx, applianceID).Execute()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	if !appliance.GetActivated() {
+		return diag.Errorf("Can not reboot the inactive appliance %q, the appliance need to be seeded first.", appliance.GetName())
+	}
+	if switchPartition {
+		_, err = api.AppliancesIdSwitchPartitionPost(ctx, applianceID).Execute()
+	} else {
+		_, err = api.AppliancesIdRebootPost(ctx, applianceID).Execute()
+	}
+	if err != nil {
+		return diag.Errorf("Could not reboot appliance %s, %s", appliance.GetName(), prettyPrintAPIError(err))
+	}
+	d.SetId(applianceID)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = d.Timeout(schema.TimeoutCreate) - durationPadding
+	if err := waitForApplianceRestart(ctx, meta, applianceID, applianceReadyState(appliance), b); err != nil {
+		// the appliance is rebooted, an error would taint the resource and reboot it again on the
+		// next apply. Keep it in state with a warning, and the state observed.
+		return append(resourceAppgateApplianceRebootRead(ctx, d, meta), diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Appliance %s was rebooted, but never reached a healthy state", appliance.GetName()),
+			Detail:   err.Error(),
+		})
+	}
+	return resourceAppgateApplianceRebootRead(ctx, d, meta)
+}
+
+func resourceAppgateApplianceRebootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading appgatesdp_appliance_reboot: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	_, res, err := api.AppliancesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), d.Id()).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	status, err := getApplianceStatus(ctx, meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("appliance_id", d.Id())
+	d.Set("state", status.GetState())
+	return nil
+}
+
+// oneShotActionResource completes a resource whose Create runs an action on the controller that can not be
+// undone, such as a reboot or a token revocation. The resource is a record of the action: Read has nothing to
+// read back from the controller, unless r has its own ReadContext, and Delete only removes it from the state.
+// triggers describes what changing the triggers attribute does, e.g. "reboot the appliance again".
+func oneShotActionResource(r *schema.Resource, triggers string) *schema.Resource {
+	if r.ReadContext == nil {
+		r.ReadContext = oneShotActionRead
+	}
+	r.DeleteContext = oneShotActionDelete
+	r.Schema["triggers"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Description: "Arbitrary values that " + triggers + " when they change.",
+		Optional:    true,
+		ForceNew:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+	return r
+}
+
+// setOneShotActionID records the action in the state under a new ID, with the time of the action in attribute.
+func setOneShotActionID(d *schema.ResourceData, attribute string) {
+	d.SetId(uuid.New().String())
+	d.Set(attribute, time.Now().UTC().Format(time.RFC3339))
+}
+
+func oneShotActionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading one-shot action: %s", d.Id())
+	return nil
+}
+
+func oneShotActionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting one-shot action: %s", d.Id())
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,69 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccApplianceReboot(t *testing.T) {
+	applianceID := os.Getenv("APPGATE_REBOOT_APPLIANCE_ID")
+	if len(applianceID) == 0 {
+		t.Skip("APPGATE_REBOOT_APPLIANCE_ID must be set for the appliance reboot acceptance test")
+	}
+	resourceName := "appgatesdp_appliance_reboot.reboot"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "appgatesdp_appliance_reboot" "reboot" {
+  appliance_id = "%s"
+  triggers = {
+    change = "1"
+  }
+}
+`, applianceID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "appliance_id", applianceID),
+					resource.TestCheckResourceAttr(resourceName, "switch_partition", "false"),
+					resource.TestCheckResourceAttr(resourceName, "state", ApplianceStateApplianceReady),
+				),
+			},
+		},
+	})
+}
+
+func TestOneShotActionResource(t *testing.T) {
+	r := oneShotActionResource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"done_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}, "run the action again")
+	if err := r.InternalValidate(nil, true); err != nil {
+		t.Fatalf("InternalValidate() error = %v", err)
+	}
+	if got := r.Schema["triggers"].Description; got != "Arbitrary values that run the action again when they change." {
+		t.Errorf("triggers description = %q", got)
+	}
+
+	d := r.TestResourceData()
+	setOneShotActionID(d, "done_at")
+	if d.Id() == "" || d.Get("done_at").(string) == "" {
+		t.Fatalf("setOneShotActionID() id = %q, done_at = %q", d.Id(), d.Get("done_at"))
+	}
+	if diags := r.ReadContext(context.Background(), d, nil); diags.HasError() || d.Id() == "" {
+		t.Fatalf("Read() = %v, id = %q", diags, d.Id())
+	}
+	if diags := r.DeleteContext(context.Background(), d, nil); diags.HasError() || d.Id() != "" {
+		t.Fatalf("Delete() = %v, id = %q", diags, d.Id())
+	}
+}
@@ -85,6 +85,9 @@ func TestAccApplianceBasicController(t *testing.T) {
 					testAccCheckApplianceExists(resourceName),
 					resource.TestCheckResourceAttr(resourceName, "name", rName),
 					resource.TestCheckResourceAttr(resourceName, "notes", "Managed by terraform"),
+					resource.TestCheckResourceAttr(resourceName, "drain_on_destroy", "false"),
+					resource.TestCheckResourceAttr(resourceName, "wipe_on_destroy", "true"),
+					resource.TestCheckResourceAttr(resourceName, "deactivate_only", "false"),
 					resource.TestCheckResourceAttr(resourceName, "hostname", context["hostname"].(string)),
 
 					resource.TestCheckResourceAttr(resourceName, "client_interface.#", "1"),
@@ -0,0 +1,186 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAppgateApplianceUpgrade() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateApplianceUpgradeCreate,
+		ReadContext:   resourceAppgateApplianceUpgradeRead,
+		DeleteContext: resourceAppgateApplianceUpgradeDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"appliance_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the appliance to upgrade.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"image_url": {
+				Type:         schema.TypeString,
+				Description:  "URL of the upgrade image, reachable from the appliance.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "controller"}),
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Description: "Version of the upgrade image. If the appliance already runs this version, the upgrade is skipped.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"switch_partition": {
+				Type:        schema.TypeBool,
+				Description: "Reboot into the new version when the upgrade completes. If false, the new version is used after the next reboot.",
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "Status of the upgrade.",
+				Computed:    true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Description: "State of the appliance.",
+				Computed:    true,
+			},
+			"current_version": {
+				Type:        schema.TypeString,
+				Description: "Version the appliance runs.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceAppgateApplianceUpgradeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := d.Get("appliance_id").(string)
+	log.Printf("[DEBUG] Creating appgatesdp_appliance_upgrade: %s", id)
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	appliance, res, err := api.AppliancesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), id).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			return diag.Errorf("Appliance %s not found", id)
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	skipped, err := upgradeAppliance(ctx, meta, appliance, d.Get("image_url").(string), d.Get("version").(string), d.Get("switch_partition").(bool), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		var incomplete *ApplianceUpgradeIncompleteError
+		if !errors.As(err, &incomplete) {
+			return diag.FromErr(err)
+		}
+		// the image is installed, an error would taint the resource and prepare the upgrade again on the
+		// next apply. Keep it in state with a warning, and the failed status.
+		d.SetId(id)
+		d.Set("status", UpgradeStatusFailed)
+		return append(resourceAppgateApplianceUpgradeRead(ctx, d, meta), diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Appliance %s was upgraded, but is not healthy", appliance.GetName()),
+			Detail:   err.Error(),
+		})
+	}
+	d.SetId(id)
+	d.Set("status", UpgradeStatusSuccess)
+	diags := resourceAppgateApplianceUpgradeRead(ctx, d, meta)
+	if v, ok := d.GetOk("version"); ok && !skipped && d.Get("switch_partition").(bool) && !applianceRunsVersion(d.Get("current_version").(string), v.(string)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Appliance %s runs %s after the upgrade, expected %s", appliance.GetName(), d.Get("current_version"), v),
+		})
+	}
+	return diags
+}
+
+func resourceAppgateApplianceUpgradeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading appgatesdp_appliance_upgrade: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.AppliancesApi
+	_, res, err := api.AppliancesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), d.Id()).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Appliance, %w", prettyPrintAPIError(err)))
+	}
+	status, err := getApplianceStatus(ctx, meta, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("appliance_id", d.Id())
+	d.Set("state", status.GetState())
+	d.Set("current_version", status.GetVersion())
+	return nil
+}
+
+func resourceAppgateApplianceUpgradeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting appgatesdp_appliance_upgrade: %s", d.Id())
+	// An installed upgrade can not be rolled back, only a prepared upgrade image can be removed.
+	status, _, err := getApplianceUpgradeStatus(ctx, meta, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Could not get upgrade status on appliance %s: %s", d.Id(), err)
+		d.SetId("")
+		return nil
+	}
+	if status == UpgradeStatusReady || status == UpgradeStatusFailed {
+		if err := cancelApplianceUpgrade(ctx, meta, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+// applianceRunsVersion returns true if the version reported in the appliance stats, e.g. 6.2.1-29983-release,
+// matches version, e.g. 6.2.1 or 6.2.1-29983.
+func applianceRunsVersion(current, version string) bool {
+	if len(current) == 0 || len(version) == 0 {
+		return false
+	}
+	return current == version || strings.HasPrefix(current, version+"-")
+}
+
+// upgradeImageVersionRegex matches the version in the file name of an upgrade image, e.g. 6.2.1-29983 in
+// appgate-6.2.1-29983-release.img.zip.
+var upgradeImageVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+(?:-\d+)?`)
+
+// upgradeImageVersion returns the version in the file name of the upgrade image at imageURL,
+// or an empty string if the file name has no version.
+func upgradeImageVersion(imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return ""
+	}
+	return upgradeImageVersionRegex.FindString(path.Base(u.Host + u.Path))
+}
@@ -0,0 +1,77 @@
+package appgate
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestApplianceRunsVersion(t *testing.T) {
+	tests := []struct {
+		current, version string
+		want             bool
+	}{
+		{current: "6.2.1-29983-release", version: "6.2.1", want: true},
+		{current: "6.2.1-29983-release", version: "6.2.1-29983", want: true},
+		{current: "6.2.1-29983-release", version: "6.2.1-29983-release", want: true},
+		{current: "6.2.10-30001-release", version: "6.2.1", want: false},
+		{current: "6.2.0-29001-release", version: "6.2.1", want: false},
+		{current: "", version: "6.2.1", want: false},
+		{current: "6.2.1-29983-release", version: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := applianceRunsVersion(tt.current, tt.version); got != tt.want {
+			t.Errorf("applianceRunsVersion(%q, %q) = %v, want %v", tt.current, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestUpgradeImageVersion(t *testing.T) {
+	tests := map[string]string{
+		"https://images.company.com/appgate-6.2.1-29983-release.img.zip": "6.2.1-29983",
+		"controller://appgate-6.3.0-31234-release.img.zip":               "6.3.0-31234",
+		"https://images.company.com/6.2.1/appgate.img.zip":               "",
+		"https://images.company.com/appgate-6.2.1.img.zip?token=1.2.3":   "6.2.1",
+		"https://images.company.com/latest.img.zip":                      "",
+	}
+	for imageURL, want := range tests {
+		if got := upgradeImageVersion(imageURL); got != want {
+			t.Errorf("upgradeImageVersion(%q) = %q, want %q", imageURL, got, want)
+		}
+	}
+}
+
+// TestAccApplianceUpgrade upgrades the appliance APPGATE_UPGRADE_APPLIANCE_ID with the image APPGATE_UPGRADE_IMAGE_URL.
+func TestAccApplianceUpgrade(t *testing.T) {
+	applianceID, imageURL := os.Getenv("APPGATE_UPGRADE_APPLIANCE_ID"), os.Getenv("APPGATE_UPGRADE_IMAGE_URL")
+	if applianceID == "" || imageURL == "" {
+		t.Skip("APPGATE_UPGRADE_APPLIANCE_ID and APPGATE_UPGRADE_IMAGE_URL must be set for the appliance upgrade test")
+	}
+	resourceName := "appgatesdp_appliance_upgrade.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccApplianceUpgrade(applianceID, imageURL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "appliance_id", applianceID),
+					resource.TestCheckResourceAttr(resourceName, "status", UpgradeStatusSuccess),
+					resource.TestCheckResourceAttrSet(resourceName, "current_version"),
+					resource.TestCheckResourceAttrSet(resourceName, "state"),
+				),
+			},
+		},
+	})
+}
+
+func testAccApplianceUpgrade(applianceID, imageURL string) string {
+	return fmt.Sprintf(`
+resource "appgatesdp_appliance_upgrade" "test" {
+  appliance_id = "%s"
+  image_url    = "%s"
+}
+`, applianceID, imageURL)
+}
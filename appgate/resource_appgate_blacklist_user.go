@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAppgateBlacklistUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAppgateBlacklistUserCreate,
-		Read:   resourceAppgateBlacklistUserRead,
-		Delete: resourceAppgateBlacklistUserDelete,
+		Create:        resourceAppgateBlacklistUserCreate,
+		Read:          resourceAppgateBlacklistUserRead,
+		Update:        resourceAppgateBlacklistUserUpdate,
+		Delete:        resourceAppgateBlacklistUserDelete,
+		CustomizeDiff: resourceAppgateBlacklistUserCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -44,6 +49,32 @@ func resourceAppgateBlacklistUser() *schema.Resource {
 				ForceNew: true,
 				Optional: true,
 			},
+			"expires_at": {
+				Type:          schema.TypeString,
+				Description:   "Remove the entry from the blacklist on the first apply after this time, in RFC 3339 format.",
+				ForceNew:      true,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"ttl"},
+				ValidateFunc:  validation.IsRFC3339Time,
+			},
+			"ttl": {
+				Type:         schema.TypeString,
+				Description:  "Remove the entry from the blacklist on the first apply after this duration, such as 72h. Sets expires_at when the entry is created.",
+				ForceNew:     true,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Description: "Whether expires_at has passed.",
+				Computed:    true,
+			},
+			"blocked": {
+				Type:        schema.TypeBool,
+				Description: "Whether the entry is on the blacklist of the controller.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -69,6 +100,13 @@ func resourceAppgateBlacklistUserCreate(d *schema.ResourceData, meta interface{}
 	if v, ok := d.GetOk("reason"); ok {
 		args.SetReason(v.(string))
 	}
+	if v, ok := d.GetOk("ttl"); ok {
+		ttl, _ := time.ParseDuration(v.(string))
+		d.Set("expires_at", time.Now().Add(ttl).UTC().Format(time.RFC3339))
+	}
+	if blacklistEntryExpired(d.Get("expires_at").(string), time.Now()) {
+		return fmt.Errorf("Could not create blacklisted user, expires_at %s has already passed", d.Get("expires_at"))
+	}
 	request := api.BlacklistPost(BaseAuthContext(token))
 	request = request.BlacklistEntry(*args)
 
@@ -82,6 +120,7 @@ func resourceAppgateBlacklistUserCreate(d *schema.ResourceData, meta interface{}
 	return resourceAppgateBlacklistUserRead(d, meta)
 }
 
+// queryEntry returns the blacklist entry of distinguishedName, or nil if the user is not blacklisted.
 func queryEntry(ctx context.Context, api *openapi.BlacklistedUsersApiService, token, distinguishedName string) (*openapi.BlacklistEntry, error) {
 	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
 	request := api.BlacklistGet(ctx)
@@ -94,7 +133,16 @@ func queryEntry(ctx context.Context, api *openapi.BlacklistedUsersApiService, to
 			return &s, nil
 		}
 	}
-	return nil, fmt.Errorf("Failed to find blacklist user %s", distinguishedName)
+	return nil, nil
+}
+
+// blacklistEntryExpired returns true if expiresAt is set and has passed.
+func blacklistEntryExpired(expiresAt string, now time.Time) bool {
+	if len(expiresAt) == 0 {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	return err == nil && !now.Before(t)
 }
 
 func resourceAppgateBlacklistUserRead(d *schema.ResourceData, meta interface{}) error {
@@ -106,8 +154,21 @@ func resourceAppgateBlacklistUserRead(d *schema.ResourceData, meta interface{})
 	api := meta.(*Client).API.BlacklistedUsersApi
 	entry, err := queryEntry(context.TODO(), api, token, d.Id())
 	if err != nil {
-		return err
+		return fmt.Errorf("Failed to read blacklisted user %w", prettyPrintAPIError(err))
+	}
+	expired := blacklistEntryExpired(d.Get("expires_at").(string), time.Now())
+	d.Set("expired", expired)
+	if entry == nil {
+		if expired {
+			// removed when it expired, keep it in the state so it is not blacklisted again.
+			d.Set("blocked", false)
+			return nil
+		}
+		log.Printf("[WARN] Blacklisted user %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
+	d.Set("blocked", true)
 
 	d.Set("user_distinguished_name", entry.GetUserDistinguishedName())
 	d.Set("username", entry.GetUsername())
@@ -116,16 +177,49 @@ func resourceAppgateBlacklistUserRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// resourceAppgateBlacklistUserCustomizeDiff plans the removal of the entry from the controller when it has expired.
+func resourceAppgateBlacklistUserCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || diff.HasChanges("user_distinguished_name", "username", "provider_name", "reason", "expires_at", "ttl") {
+		return nil
+	}
+	if diff.Get("expired").(bool) && diff.Get("blocked").(bool) {
+		return diff.SetNew("blocked", false)
+	}
+	return nil
+}
+
+func resourceAppgateBlacklistUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	// all other attributes are ForceNew, so the only update is the removal of an expired entry.
+	if d.HasChange("blocked") && !d.Get("blocked").(bool) {
+		log.Printf("[DEBUG] Removing expired blacklisted user %s", d.Id())
+		if err := deleteBlacklistEntry(d, meta); err != nil {
+			return err
+		}
+	}
+	return resourceAppgateBlacklistUserRead(d, meta)
+}
+
 func resourceAppgateBlacklistUserDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Reading blacklisted user id: %+v", d.Id())
+	if err := deleteBlacklistEntry(d, meta); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func deleteBlacklistEntry(d *schema.ResourceData, meta interface{}) error {
 	token, err := meta.(*Client).GetToken()
 	if err != nil {
 		return err
 	}
 	api := meta.(*Client).API.BlacklistedUsersApi
-	if _, err := api.BlacklistDistinguishedNameDelete(BaseAuthContext(token), d.Id()).Execute(); err != nil {
+	response, err := api.BlacklistDistinguishedNameDelete(BaseAuthContext(token), d.Id()).Execute()
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil
+		}
 		return fmt.Errorf("Could not delete blacklisted user %w", prettyPrintAPIError(err))
 	}
-	d.SetId("")
 	return nil
 }
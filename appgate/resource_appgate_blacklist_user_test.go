@@ -3,6 +3,7 @@ package appgate
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -49,3 +50,71 @@ func testAccBlacklistUserImportStateCheckFunc(expectedStates int) resource.Impor
 		return nil
 	}
 }
+
+func TestAccBlacklistUserTTL(t *testing.T) {
+	resourceName := "appgatesdp_blacklist_user.contractor"
+	dataSourceName := "data.appgatesdp_blacklisted_users.local"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckBlacklistUserTTL(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user_distinguished_name", fmt.Sprintf("CN=%s,OU=local", rName)),
+					resource.TestCheckResourceAttr(resourceName, "ttl", "72h"),
+					resource.TestCheckResourceAttrSet(resourceName, "expires_at"),
+					resource.TestCheckResourceAttr(resourceName, "expired", "false"),
+					resource.TestCheckResourceAttr(resourceName, "blocked", "true"),
+				),
+			},
+			{
+				// the data source is read after the entry is created.
+				Config: testAccCheckBlacklistUserTTL(rName) + testAccBlacklistedUsersDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "blacklisted_users.*", map[string]string{
+						"user_distinguished_name": fmt.Sprintf("CN=%s,OU=local", rName),
+						"username":                rName,
+						"provider_name":           "local",
+						"reason":                  "contractor offboarding",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBlacklistUserTTL(rName string) string {
+	return fmt.Sprintf(`
+resource "appgatesdp_blacklist_user" "contractor" {
+  user_distinguished_name = "CN=%s,OU=local"
+  reason                  = "contractor offboarding"
+  ttl                     = "72h"
+}
+`, rName)
+}
+
+const testAccBlacklistedUsersDataSource = `
+data "appgatesdp_blacklisted_users" "local" {
+  provider_name = "local"
+}
+`
+
+func TestBlacklistEntryExpired(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	tests := map[string]bool{
+		"":                          false,
+		"2024-05-02T11:59:59Z":      true,
+		"2024-05-02T12:00:00Z":      true,
+		"2024-05-02T12:00:01Z":      false,
+		"2024-05-02T13:00:00+02:00": true,
+		"not a time":                false,
+	}
+	for expiresAt, want := range tests {
+		if got := blacklistEntryExpired(expiresAt, now); got != want {
+			t.Errorf("blacklistEntryExpired(%q) = %t, want %t", expiresAt, got, want)
+		}
+	}
+}
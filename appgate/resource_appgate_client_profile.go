@@ -49,6 +49,8 @@ func resourceAppgateClientProfile() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"spa_key_name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -130,6 +132,7 @@ func resourceAppgateClientProfileRead(ctx context.Context, d *schema.ResourceDat
 		}
 		return diag.FromErr(fmt.Errorf("Failed to read client profile, %s", err))
 	}
+	setUpdated(d, updatedFromMap(profile))
 
 	id, ok := profile["id"].(string)
 	if ok {
@@ -183,6 +186,9 @@ func resourceAppgateClientProfileUpdate(ctx context.Context, d *schema.ResourceD
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("Failed to read profile while updating, %w", err))
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateClientProfile(), updatedFromMap(originalProfile)); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		originalProfile["name"] = d.Get("name").(string)
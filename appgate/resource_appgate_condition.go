@@ -17,7 +17,7 @@ func resourceAppgateCondition() *schema.Resource {
 		Update: resourceAppgateConditionUpdate,
 		Delete: resourceAppgateConditionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importPreventDestroyIfReferenced,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -46,6 +46,10 @@ func resourceAppgateCondition() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
+			"prevent_destroy_if_referenced": preventDestroyIfReferencedSchema(),
+
 			"expression": {
 				Type:        schema.TypeString,
 				Description: "Boolean expression in JavaScript.",
@@ -192,6 +196,7 @@ func resourceAppgateConditionRead(d *schema.ResourceData, meta interface{}) erro
 		}
 		return fmt.Errorf("Failed to read Condition, %w", err)
 	}
+	setUpdated(d, remoteCondition.GetUpdated())
 	d.SetId(remoteCondition.GetId())
 	d.Set("condition_id", remoteCondition.Id)
 	d.Set("name", remoteCondition.Name)
@@ -235,6 +240,9 @@ func resourceAppgateConditionUpdate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return fmt.Errorf("Failed to read condition, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateCondition(), orginalCondition.GetUpdated()); err != nil {
+		return err
+	}
 	if d.HasChange("name") {
 		orginalCondition.SetName(d.Get("name").(string))
 	}
@@ -291,6 +299,9 @@ func resourceAppgateConditionDelete(d *schema.ResourceData, meta interface{}) er
 
 	// Get condition
 	ctx := BaseAuthContext(token)
+	if err := checkReferencesBeforeDelete(ctx, d, meta, "Condition", referenceQuery{ID: d.Id()}); err != nil {
+		return err
+	}
 	request := api.ConditionsIdGet(ctx, d.Id())
 	condition, _, err := request.Execute()
 	if err != nil {
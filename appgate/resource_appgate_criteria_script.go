@@ -18,7 +18,7 @@ func resourceAppgateCriteriaScript() *schema.Resource {
 		Update: resourceAppgateCriteriaScriptUpdate,
 		Delete: resourceAppgateCriteriaScriptDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importPreventDestroyIfReferenced,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -47,6 +47,10 @@ func resourceAppgateCriteriaScript() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
+			"prevent_destroy_if_referenced": preventDestroyIfReferencedSchema(),
+
 			"expression": {
 				Type:        schema.TypeString,
 				Description: "A JavaScript expression that returns boolean.",
@@ -106,6 +110,7 @@ func resourceAppgateCriteriaScriptRead(d *schema.ResourceData, meta interface{})
 		}
 		return fmt.Errorf("Failed to read Criteria script, %w", err)
 	}
+	setUpdated(d, criteraScript.GetUpdated())
 	d.SetId(criteraScript.GetId())
 	d.Set("criteria_script_id", criteraScript.GetId())
 	d.Set("name", criteraScript.GetName())
@@ -130,6 +135,9 @@ func resourceAppgateCriteriaScriptUpdate(d *schema.ResourceData, meta interface{
 	if err != nil {
 		return fmt.Errorf("Failed to read Criteria script while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateCriteriaScript(), originalCriteriaScript.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalCriteriaScript.SetName(d.Get("name").(string))
@@ -163,6 +171,10 @@ func resourceAppgateCriteriaScriptDelete(d *schema.ResourceData, meta interface{
 	if err != nil {
 		return err
 	}
+	q := referenceQuery{ID: d.Id(), Name: d.Get("name").(string)}
+	if err := checkReferencesBeforeDelete(BaseAuthContext(token), d, meta, "Criteria script", q); err != nil {
+		return err
+	}
 	api := meta.(*Client).API.CriteriaScriptsApi
 	if _, err := api.CriteriaScriptsIdDelete(BaseAuthContext(token), d.Id()).Execute(); err != nil {
 		return fmt.Errorf("Could not delete Criteria script %w", prettyPrintAPIError(err))
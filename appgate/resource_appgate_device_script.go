@@ -47,6 +47,8 @@ func resourceAppgateDeviceScript() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"filename": {
 				Type:        schema.TypeString,
 				Description: "The name of the file to be downloaded as to the client devices.",
@@ -131,6 +133,7 @@ func resourceAppgateDeviceScriptRead(d *schema.ResourceData, meta interface{}) e
 		}
 		return fmt.Errorf("Failed to read Device script, %w", err)
 	}
+	setUpdated(d, deviceScript.GetUpdated())
 	d.SetId(deviceScript.GetId())
 	d.Set("device_script_id", deviceScript.GetId())
 	d.Set("name", deviceScript.GetName())
@@ -155,6 +158,9 @@ func resourceAppgateDeviceScriptUpdate(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return fmt.Errorf("Failed to read Device script while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateDeviceScript(), originalDeviceScript.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalDeviceScript.SetName(d.Get("name").(string))
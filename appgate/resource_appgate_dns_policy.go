@@ -16,7 +16,7 @@ func resourceAppgateDnsPolicy() *schema.Resource {
 		UpdateContext: resourceAppgatePolicyUpdate,
 		DeleteContext: resourceAppgatePolicyDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAppgatePolicyImport,
 		},
 
 		Schema: func() map[string]*schema.Schema {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"sort"
 	"strings"
 	"time"
 
@@ -24,8 +23,13 @@ func resourceAppgateEntitlement() *schema.Resource {
 		ReadContext:   resourceAppgateEntitlementRuleRead,
 		UpdateContext: resourceAppgateEntitlementRuleUpdate,
 		DeleteContext: resourceAppgateEntitlementRuleDelete,
+		CustomizeDiff: resourceAppgateEntitlementActionsCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				// imported entitlements owns all actions until configured otherwise.
+				d.Set("actions_authoritative", true)
+				return []*schema.ResourceData{d}, nil
+			},
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -53,6 +57,8 @@ func resourceAppgateEntitlement() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"disabled": {
 				Type:     schema.TypeBool,
 				Default:  false,
@@ -98,78 +104,21 @@ func resourceAppgateEntitlement() *schema.Resource {
 
 			"actions": {
 				Type:             schema.TypeSet,
-				Required:         true,
+				Optional:         true,
 				Set:              resourceAppgateEntitlementActionHash,
 				DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-
-						"subtype": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-
-						"action": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-
-						"hosts": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							Set:      schema.HashString,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
-
-						"ports": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							Set:      schema.HashString,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
-
-						"types": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
-
-						"methods": {
-							Type:             schema.TypeSet,
-							Optional:         true,
-							Computed:         true,
-							DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
-							Set:              schema.HashString,
-							Elem:             &schema.Schema{Type: schema.TypeString},
-						},
-
-						"monitor": {
-							Type:             schema.TypeList,
-							MaxItems:         1,
-							Optional:         true,
-							Computed:         true,
-							DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"enabled": {
-										Type:             schema.TypeBool,
-										Optional:         true,
-										Computed:         true,
-										DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
-									},
-									"timeout": {
-										Type:             schema.TypeInt,
-										Optional:         true,
-										Computed:         true,
-										DiffSuppressFunc: suppressMissingOptionalConfigurationBlock,
-									},
-								},
-							},
-						},
-					},
+					Schema: entitlementActionSchema(),
 				},
 			},
 
+			"actions_authoritative": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If false, actions not declared in this resource, for example managed by appgatesdp_entitlement_action, are left untouched.",
+			},
+
 			"app_shortcuts": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -215,27 +164,22 @@ func resourceAppgateEntitlementActionHash(v interface{}) int {
 	var buf bytes.Buffer
 	buf.WriteString(fmt.Sprintf("%s-", copy["subtype"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", copy["action"].(string)))
+	// hosts, ports, types and methods are normalized so equivalent forms,
+	// for example "80-80" and "80", does not produce a diff.
 	if v, ok := copy["hosts"]; ok {
-		buf.WriteString(fmt.Sprintf("%v-", v.(*schema.Set).List()))
+		buf.WriteString(fmt.Sprintf("%v-", normalizeStringSet(v, normalizeEntitlementActionHost)))
 	}
 	if v, ok := copy["ports"]; ok {
-		buf.WriteString(fmt.Sprintf("%v-", v.(*schema.Set).List()))
+		buf.WriteString(fmt.Sprintf("%v-", normalizeStringSet(v, normalizeNumberRange)))
 	}
 	if v, ok := copy["types"]; ok {
-		vs := v.([]interface{})
-		s := make([]string, len(vs))
-		for i, raw := range vs {
-			s[i] = raw.(string)
-		}
-		sort.Strings(s)
-
-		for _, v := range s {
+		for _, v := range normalizeStringSet(v, normalizeNumberRange) {
 			buf.WriteString(fmt.Sprintf("%s-", v))
 		}
 	}
 
 	if v, ok := copy["methods"]; ok {
-		buf.WriteString(fmt.Sprintf("%v-", v.(*schema.Set).List()))
+		buf.WriteString(fmt.Sprintf("%v-", normalizeStringSet(v, strings.ToUpper)))
 	}
 
 	// monitor is only valid if subtype is tcp_up
@@ -370,6 +314,7 @@ func resourceAppgateEntitlementRuleRead(ctx context.Context, d *schema.ResourceD
 		}
 		return diag.FromErr(fmt.Errorf("Failed to read Entitlement, %w", err))
 	}
+	setUpdated(d, entitlement.GetUpdated())
 	d.SetId(entitlement.GetId())
 	d.Set("entitlement_id", entitlement.GetId())
 	d.Set("name", entitlement.GetName())
@@ -390,6 +335,9 @@ func resourceAppgateEntitlementRuleRead(ctx context.Context, d *schema.ResourceD
 	}
 
 	actions := flattenEntitlementActions(entitlement.GetActions(), d)
+	if !d.Get("actions_authoritative").(bool) {
+		actions = filterEntitlementActions(actions, d.Get("actions").(*schema.Set))
+	}
 	if err = d.Set("actions", actions); err != nil {
 		return diag.FromErr(err)
 	}
@@ -423,20 +371,8 @@ func icmpTypes() []string {
 func flattenEntitlementActions(actions []openapi.EntitlementAllOfActions, d *schema.ResourceData) *schema.Set {
 	out := []interface{}{}
 	for _, act := range actions {
-		action := make(map[string]interface{})
-		action["subtype"] = act.GetSubtype()
-		action["action"] = act.GetAction()
-		action["hosts"] = schema.NewSet(schema.HashString, convertStringArrToInterface(act.GetHosts()))
-		action["ports"] = schema.NewSet(schema.HashString, convertStringArrToInterface(act.GetPorts()))
-		types := act.GetTypes()
-		if types != nil && inArray(act.GetSubtype(), icmpTypes()) {
-			action["types"] = convertStringArrToInterface(act.GetTypes())
-		}
-		if v, ok := act.GetMethodsOk(); ok {
-			action["methods"] = schema.NewSet(schema.HashString, convertStringArrToInterface(v))
-		}
+		action := flattenEntitlementAction(act)
 		if act.Monitor != nil && act.GetSubtype() == "tcp_up" {
-			action["monitor"] = flattenEntitlementActionMonitor(act.GetMonitor())
 			dataActions := d.Get("actions")
 			hash := resourceAppgateEntitlementActionHash(action)
 
@@ -455,6 +391,60 @@ func flattenEntitlementActions(actions []openapi.EntitlementAllOfActions, d *sch
 	return schema.NewSet(resourceAppgateEntitlementActionHash, out)
 }
 
+func flattenEntitlementAction(act openapi.EntitlementAllOfActions) map[string]interface{} {
+	action := make(map[string]interface{})
+	action["subtype"] = act.GetSubtype()
+	action["action"] = act.GetAction()
+	action["hosts"] = schema.NewSet(hashEntitlementActionHost, convertStringArrToInterface(act.GetHosts()))
+	action["ports"] = schema.NewSet(hashEntitlementActionPort, convertStringArrToInterface(act.GetPorts()))
+	types := act.GetTypes()
+	if types != nil && inArray(act.GetSubtype(), icmpTypes()) {
+		action["types"] = convertStringArrToInterface(act.GetTypes())
+	}
+	if v, ok := act.GetMethodsOk(); ok {
+		action["methods"] = schema.NewSet(hashEntitlementActionMethod, convertStringArrToInterface(v))
+	}
+	if act.Monitor != nil && act.GetSubtype() == "tcp_up" {
+		action["monitor"] = flattenEntitlementActionMonitor(act.GetMonitor())
+	}
+	return action
+}
+
+// filterEntitlementActions returns the actions which are part of managed, used when
+// actions_authoritative is false to ignore actions added by appgatesdp_entitlement_action
+// or by administrators.
+func filterEntitlementActions(actions *schema.Set, managed *schema.Set) *schema.Set {
+	out := []interface{}{}
+	for _, action := range actions.List() {
+		if managed.Contains(action) {
+			out = append(out, action)
+		}
+	}
+	return schema.NewSet(resourceAppgateEntitlementActionHash, out)
+}
+
+// mergeEntitlementActions replaces the previously managed actions in remote with
+// the configured actions, and leaves everything else untouched.
+func mergeEntitlementActions(remote []openapi.EntitlementAllOfActions, old, new []openapi.EntitlementAllOfActions) []openapi.EntitlementAllOfActions {
+	oldHashes := make(map[int]bool, len(old))
+	for _, action := range old {
+		oldHashes[resourceAppgateEntitlementActionHash(flattenEntitlementAction(action))] = true
+	}
+	newHashes := make(map[int]bool, len(new))
+	for _, action := range new {
+		newHashes[resourceAppgateEntitlementActionHash(flattenEntitlementAction(action))] = true
+	}
+	result := make([]openapi.EntitlementAllOfActions, 0, len(remote)+len(new))
+	for _, action := range remote {
+		hash := resourceAppgateEntitlementActionHash(flattenEntitlementAction(action))
+		if oldHashes[hash] || newHashes[hash] {
+			continue
+		}
+		result = append(result, action)
+	}
+	return append(result, new...)
+}
+
 func flattenEntitlementActionMonitor(monitor openapi.EntitlementAllOfMonitor) []interface{} {
 	m := make(map[string]interface{})
 	m["enabled"] = monitor.GetEnabled()
@@ -473,6 +463,9 @@ func resourceAppgateEntitlementRuleUpdate(ctx context.Context, d *schema.Resourc
 	}
 	api := meta.(*Client).API.EntitlementsApi
 	currentVersion := meta.(*Client).ApplianceVersion
+	// appgatesdp_entitlement_action may update the same entitlement concurrently.
+	resourceLocks.Lock(d.Id())
+	defer resourceLocks.Unlock(d.Id())
 	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
 	request := api.EntitlementsIdGet(ctx, d.Id())
 	orginalEntitlment, response, err := request.Execute()
@@ -483,6 +476,9 @@ func resourceAppgateEntitlementRuleUpdate(ctx context.Context, d *schema.Resourc
 		}
 		return diag.FromErr(fmt.Errorf("Failed to read Entitlement while updating, %w", err))
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateEntitlement(), orginalEntitlment.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		orginalEntitlment.SetName(d.Get("name").(string))
@@ -522,11 +518,18 @@ func resourceAppgateEntitlementRuleUpdate(ctx context.Context, d *schema.Resourc
 	}
 
 	if d.HasChange("actions") {
-		_, v := d.GetChange("actions")
+		o, v := d.GetChange("actions")
 		actions, _, err := readEntitlmentActionsFromConfig(v.(*schema.Set).List(), diags, currentVersion)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		if !d.Get("actions_authoritative").(bool) {
+			oldActions, _, err := readEntitlmentActionsFromConfig(o.(*schema.Set).List(), diags, currentVersion)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			actions = mergeEntitlementActions(orginalEntitlment.GetActions(), oldActions, actions)
+		}
 		orginalEntitlment.SetActions(actions)
 	}
 
@@ -585,6 +588,9 @@ func readEntitlmentActionsFromConfig(actions []interface{}, diags diag.Diagnosti
 		}
 		a := openapi.EntitlementAllOfActions{}
 		raw := action.(map[string]interface{})
+		if err := validateEntitlementAction(raw); err != nil {
+			return result, diags, err
+		}
 		if v, ok := raw["subtype"]; ok {
 			a.SetSubtype(v.(string))
 		}
@@ -596,6 +602,9 @@ func readEntitlmentActionsFromConfig(actions []interface{}, diags diag.Diagnosti
 			if err != nil {
 				return result, diags, fmt.Errorf("Failed to resolve entitlement action hosts: %w", err)
 			}
+			for i, host := range hosts {
+				hosts[i] = normalizeEntitlementActionHost(host)
+			}
 			a.SetHosts(hosts)
 		}
 		if v, ok := raw["ports"]; ok {
@@ -603,6 +612,9 @@ func readEntitlmentActionsFromConfig(actions []interface{}, diags diag.Diagnosti
 			if err != nil {
 				return result, diags, fmt.Errorf("Failed to resolve entitlement action ports: %w", err)
 			}
+			for i, port := range ports {
+				ports[i] = normalizeNumberRange(port)
+			}
 			a.SetPorts(ports)
 		}
 		if v := raw["types"]; len(v.([]interface{})) > 0 {
@@ -617,6 +629,9 @@ func readEntitlmentActionsFromConfig(actions []interface{}, diags diag.Diagnosti
 			if err != nil {
 				return result, diags, fmt.Errorf("Failed to resolve entitlement action types: %w", err)
 			}
+			for i, t := range types {
+				types[i] = normalizeNumberRange(t)
+			}
 			a.SetTypes(types)
 		}
 		if current.GreaterThanOrEqual(Appliance61Version) {
@@ -625,6 +640,9 @@ func readEntitlmentActionsFromConfig(actions []interface{}, diags diag.Diagnosti
 				if err != nil {
 					return result, diags, fmt.Errorf("Failed to resolve entitlement action hosts: %w", err)
 				}
+				for i, method := range methods {
+					methods[i] = strings.ToUpper(method)
+				}
 				a.SetMethods(methods)
 			}
 
@@ -0,0 +1,295 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/appgate/terraform-provider-appgatesdp/appgate/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAppgateEntitlementAction() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateEntitlementActionCreate,
+		ReadContext:   resourceAppgateEntitlementActionRead,
+		UpdateContext: resourceAppgateEntitlementActionUpdate,
+		DeleteContext: resourceAppgateEntitlementActionDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if raw := d.GetRawConfig(); !raw.IsNull() && raw.IsKnown() && !raw.IsWhollyKnown() {
+				return nil
+			}
+			return validateEntitlementAction(entitlementActionFromResourceData(d))
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAppgateEntitlementActionImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: mergeSchemaMaps(entitlementActionKeySchema(), map[string]*schema.Schema{
+			"entitlement_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the Entitlement the action is added to.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+		}),
+	}
+}
+
+// entitlementActionKeySchema is entitlementActionSchema where subtype, hosts and ports, that identifies
+// the action on the Entitlement, creates a new action when changed.
+func entitlementActionKeySchema() map[string]*schema.Schema {
+	s := entitlementActionSchema()
+	for _, key := range []string{"subtype", "hosts", "ports"} {
+		s[key].ForceNew = true
+	}
+	return s
+}
+
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// entitlementActionFromResourceData returns the action in the same format as
+// an element in appgatesdp_entitlement.actions.
+func entitlementActionFromResourceData(d resourceGetter) map[string]interface{} {
+	raw := make(map[string]interface{})
+	for _, key := range []string{"subtype", "action", "hosts", "ports", "types", "methods", "monitor"} {
+		raw[key] = d.Get(key)
+	}
+	return raw
+}
+
+// entitlementActionKey identifies an action on an Entitlement by subtype, hosts and ports, so
+// changes to the other attributes of the action are updated in place.
+func entitlementActionKey(action map[string]interface{}) string {
+	return fmt.Sprintf("%s-%v-%v",
+		action["subtype"],
+		normalizeStringSet(action["hosts"], normalizeEntitlementActionHost),
+		normalizeStringSet(action["ports"], normalizeNumberRange),
+	)
+}
+
+func entitlementActionID(entitlementID string, action map[string]interface{}) string {
+	return fmt.Sprintf("%s/%d", entitlementID, hashcode.String(entitlementActionKey(action)))
+}
+
+func findEntitlementAction(actions []openapi.EntitlementAllOfActions, action map[string]interface{}) int {
+	key := entitlementActionKey(action)
+	for i, a := range actions {
+		if entitlementActionKey(flattenEntitlementAction(a)) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateEntitlementActions does a read-modify-write of the entitlement actions, see readModifyWrite.
+func updateEntitlementActions(ctx context.Context, meta interface{}, entitlementID string, timeout time.Duration, modify func([]openapi.EntitlementAllOfActions) ([]openapi.EntitlementAllOfActions, bool, error), applied func([]openapi.EntitlementAllOfActions) bool) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.EntitlementsApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	read := func() (*openapi.Entitlement, error) {
+		entitlement, response, err := api.EntitlementsIdGet(ctx, entitlementID).Execute()
+		if err != nil {
+			if response != nil && response.StatusCode == http.StatusNotFound {
+				return nil, fmt.Errorf("Entitlement %s does not exist", entitlementID)
+			}
+			return nil, fmt.Errorf("Failed to read Entitlement %s, %w", entitlementID, prettyPrintAPIError(err))
+		}
+		return entitlement, nil
+	}
+	write := func(entitlement *openapi.Entitlement) error {
+		if _, _, err := api.EntitlementsIdPut(ctx, entitlementID).Entitlement(*entitlement).Execute(); err != nil {
+			return fmt.Errorf("Could not update Entitlement %s %w", entitlementID, prettyPrintAPIError(err))
+		}
+		return nil
+	}
+	return readModifyWrite(ctx, entitlementID, timeout, read, func(entitlement *openapi.Entitlement) (*openapi.Entitlement, bool, error) {
+		actions, changed, err := modify(entitlement.GetActions())
+		if err != nil || !changed {
+			return entitlement, changed, err
+		}
+		entitlement.SetActions(actions)
+		return entitlement, true, nil
+	}, write, func(entitlement *openapi.Entitlement) bool {
+		return applied(entitlement.GetActions())
+	})
+}
+
+func resourceAppgateEntitlementActionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	entitlementID := d.Get("entitlement_id").(string)
+	log.Printf("[DEBUG] Creating Entitlement action on %s", entitlementID)
+	raw := entitlementActionFromResourceData(d)
+	actions, diags, err := readEntitlmentActionsFromConfig([]interface{}{raw}, nil, meta.(*Client).ApplianceVersion)
+	if err != nil {
+		return AppendFromErr(diags, err)
+	}
+	err = updateEntitlementActions(ctx, meta, entitlementID, d.Timeout(schema.TimeoutCreate), func(existing []openapi.EntitlementAllOfActions) ([]openapi.EntitlementAllOfActions, bool, error) {
+		if findEntitlementAction(existing, raw) >= 0 {
+			return nil, false, fmt.Errorf("an action with the same subtype, hosts and ports already exists on Entitlement %s, import it with terraform import", entitlementID)
+		}
+		return append(existing, actions...), true, nil
+	}, func(existing []openapi.EntitlementAllOfActions) bool {
+		return findEntitlementAction(existing, raw) >= 0
+	})
+	if err != nil {
+		return AppendFromErr(diags, fmt.Errorf("Could not create Entitlement action %w", err))
+	}
+	d.SetId(entitlementActionID(entitlementID, raw))
+
+	return append(diags, resourceAppgateEntitlementActionRead(ctx, d, meta)...)
+}
+
+func resourceAppgateEntitlementActionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading Entitlement action id: %+v", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.EntitlementsApi
+	entitlementID := d.Get("entitlement_id").(string)
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	entitlement, res, err := api.EntitlementsIdGet(ctx, entitlementID).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Entitlement, %w", err))
+	}
+	actions := entitlement.GetActions()
+	i := findEntitlementAction(actions, entitlementActionFromResourceData(d))
+	if i < 0 {
+		log.Printf("[WARN] Entitlement action %s was removed outside of terraform", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err := setEntitlementActionResourceData(d, actions[i]); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func setEntitlementActionResourceData(d *schema.ResourceData, action openapi.EntitlementAllOfActions) error {
+	flat := flattenEntitlementAction(action)
+	for key, value := range flat {
+		// keep the monitor from the configuration, see flattenEntitlementActions
+		if key == "monitor" {
+			if v, ok := d.Get("monitor").([]interface{}); ok && len(v) > 0 && v[0] != nil {
+				continue
+			}
+		}
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("Failed to set Entitlement action %s, %w", key, err)
+		}
+	}
+	return nil
+}
+
+func resourceAppgateEntitlementActionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	entitlementID := d.Get("entitlement_id").(string)
+	log.Printf("[DEBUG] Updating Entitlement action id: %+v", d.Id())
+	raw := entitlementActionFromResourceData(d)
+	actions, diags, err := readEntitlmentActionsFromConfig([]interface{}{raw}, nil, meta.(*Client).ApplianceVersion)
+	if err != nil {
+		return AppendFromErr(diags, err)
+	}
+	hash := resourceAppgateEntitlementActionHash(raw)
+	err = updateEntitlementActions(ctx, meta, entitlementID, d.Timeout(schema.TimeoutUpdate), func(existing []openapi.EntitlementAllOfActions) ([]openapi.EntitlementAllOfActions, bool, error) {
+		i := findEntitlementAction(existing, raw)
+		if i < 0 {
+			return nil, false, fmt.Errorf("the action was removed outside of terraform, run terraform refresh and try again")
+		}
+		existing[i] = actions[0]
+		return existing, true, nil
+	}, func(existing []openapi.EntitlementAllOfActions) bool {
+		i := findEntitlementAction(existing, raw)
+		return i >= 0 && resourceAppgateEntitlementActionHash(flattenEntitlementAction(existing[i])) == hash
+	})
+	if err != nil {
+		return AppendFromErr(diags, fmt.Errorf("Could not update Entitlement action %w", err))
+	}
+
+	return append(diags, resourceAppgateEntitlementActionRead(ctx, d, meta)...)
+}
+
+func resourceAppgateEntitlementActionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	entitlementID := d.Get("entitlement_id").(string)
+	log.Printf("[DEBUG] Delete Entitlement action id: %+v", d.Id())
+	raw := entitlementActionFromResourceData(d)
+	err := updateEntitlementActions(ctx, meta, entitlementID, d.Timeout(schema.TimeoutDelete), func(existing []openapi.EntitlementAllOfActions) ([]openapi.EntitlementAllOfActions, bool, error) {
+		i := findEntitlementAction(existing, raw)
+		if i < 0 {
+			return existing, false, nil
+		}
+		return append(existing[:i], existing[i+1:]...), true, nil
+	}, func(existing []openapi.EntitlementAllOfActions) bool {
+		return findEntitlementAction(existing, raw) < 0
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not delete Entitlement action %w", err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceAppgateEntitlementActionImport accepts <entitlement_id>/<index>, where index is the
+// zero based position of the action on the Controller, or the resource ID <entitlement_id>/<key hash>.
+func resourceAppgateEntitlementActionImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	entitlementID, ref, found := strings.Cut(d.Id(), "/")
+	if !found {
+		return nil, fmt.Errorf("invalid import id %q, expected <entitlement_id>/<index>", d.Id())
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import id %q, expected <entitlement_id>/<index>", d.Id())
+	}
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API.EntitlementsApi
+	entitlement, _, err := api.EntitlementsIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), entitlementID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read Entitlement %s, %w", entitlementID, prettyPrintAPIError(err))
+	}
+	actions := entitlement.GetActions()
+	var action *openapi.EntitlementAllOfActions
+	for i, a := range actions {
+		if hashcode.String(entitlementActionKey(flattenEntitlementAction(a))) == n {
+			action = &actions[i]
+			break
+		}
+	}
+	if action == nil && n >= 0 && n < len(actions) {
+		action = &actions[n]
+	}
+	if action == nil {
+		return nil, fmt.Errorf("could not find action %s on Entitlement %s", ref, entitlementID)
+	}
+	d.Set("entitlement_id", entitlementID)
+	if err := setEntitlementActionResourceData(d, *action); err != nil {
+		return nil, err
+	}
+	d.SetId(entitlementActionID(entitlementID, flattenEntitlementAction(*action)))
+	return []*schema.ResourceData{d}, nil
+}
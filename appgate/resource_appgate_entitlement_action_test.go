@@ -0,0 +1,108 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccEntitlementActionNonAuthoritative(t *testing.T) {
+	entitlementName := "appgatesdp_entitlement.shared"
+	resourceName := "appgatesdp_entitlement_action.team_a"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckItemDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckEntitlementActionNonAuthoritative(rName, "443"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEntitlementExists(entitlementName),
+					testAccCheckEntitlementActionCount(entitlementName, 2),
+					resource.TestCheckResourceAttr(entitlementName, "actions_authoritative", "false"),
+					resource.TestCheckResourceAttr(entitlementName, "actions.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "entitlement_id", entitlementName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "subtype", "tcp_up"),
+					resource.TestCheckResourceAttr(resourceName, "action", "allow"),
+					resource.TestCheckResourceAttr(resourceName, "hosts.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "hosts.0", "team-a.company.com"),
+					resource.TestCheckResourceAttr(resourceName, "ports.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "ports.0", "443"),
+				),
+			},
+			{
+				Config: testAccCheckEntitlementActionNonAuthoritative(rName, "8443"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEntitlementActionCount(entitlementName, 2),
+					resource.TestCheckResourceAttr(entitlementName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "ports.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "ports.0", "8443"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckEntitlementActionCount(resource string, want int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		token, err := testAccProvider.Meta().(*Client).GetToken()
+		if err != nil {
+			return err
+		}
+		api := testAccProvider.Meta().(*Client).API.EntitlementsApi
+
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		entitlement, _, err := api.EntitlementsIdGet(BaseAuthContext(token), rs.Primary.ID).Execute()
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if got := len(entitlement.GetActions()); got != want {
+			return fmt.Errorf("expected %d actions on %s, got %d", want, resource, got)
+		}
+		return nil
+	}
+}
+
+func testAccCheckEntitlementActionNonAuthoritative(rName, port string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_site" "default_site" {
+  site_name = "Default Site"
+}
+data "appgatesdp_condition" "always" {
+  condition_name = "Always"
+}
+resource "appgatesdp_entitlement" "shared" {
+  name = "%s"
+  site = data.appgatesdp_site.default_site.id
+  conditions = [
+    data.appgatesdp_condition.always.id
+  ]
+  actions_authoritative = false
+  actions {
+    action  = "allow"
+    subtype = "tcp_up"
+    hosts   = ["shared.company.com"]
+    ports   = ["443"]
+  }
+}
+
+resource "appgatesdp_entitlement_action" "team_a" {
+  entitlement_id = appgatesdp_entitlement.shared.id
+  action         = "allow"
+  subtype        = "tcp_up"
+  hosts          = ["team-a.company.com"]
+  ports          = ["%s"]
+}
+`, rName, port)
+}
@@ -46,6 +46,8 @@ func resourceAppgateEntitlementScript() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"type": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -112,6 +114,7 @@ func resourceAppgateEntitlementScriptRead(d *schema.ResourceData, meta interface
 		}
 		return fmt.Errorf("Failed to read Entitlement script, %w", err)
 	}
+	setUpdated(d, EntitlementScript.GetUpdated())
 	d.SetId(EntitlementScript.GetId())
 	d.Set("entitlement_script_id", EntitlementScript.GetId())
 	d.Set("name", EntitlementScript.GetName())
@@ -137,6 +140,9 @@ func resourceAppgateEntitlementScriptUpdate(d *schema.ResourceData, meta interfa
 	if err != nil {
 		return fmt.Errorf("Failed to read Entitlement script while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateEntitlementScript(), originalEntitlementScript.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalEntitlementScript.SetName(d.Get("name").(string))
@@ -12,10 +12,11 @@ import (
 
 func resourceAppgateConnectorProvider() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAppgateConnectorProviderRuleCreate,
-		Read:   resourceAppgateConnectorProviderRuleRead,
-		Update: resourceAppgateConnectorProviderRuleUpdate,
-		Delete: resourceAppgateConnectorProviderRuleDelete,
+		Create:        resourceAppgateConnectorProviderRuleCreate,
+		Read:          resourceAppgateConnectorProviderRuleRead,
+		Update:        resourceAppgateConnectorProviderRuleUpdate,
+		Delete:        resourceAppgateConnectorProviderRuleDelete,
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderConnector),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -91,6 +92,7 @@ func resourceAppgateConnectorProviderRuleRead(d *schema.ResourceData, meta inter
 		d.SetId("")
 		return fmt.Errorf("Failed to read Connector Identity provider, %w", err)
 	}
+	setUpdated(d, connectorIP.GetUpdated())
 	d.SetId(connectorIP.GetId())
 
 	d.Set("type", identityProviderConnector)
@@ -133,6 +135,9 @@ func resourceAppgateConnectorProviderRuleUpdate(d *schema.ResourceData, meta int
 	if err != nil {
 		return fmt.Errorf("Failed to read Connector Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateConnectorProvider(), originalConnectorProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalConnectorProvider.SetName(d.Get("name").(string))
@@ -18,6 +18,10 @@ func resourceAppgateLdapProvider() *schema.Resource {
 		Read:   resourceAppgateLdapProviderRuleRead,
 		Update: resourceAppgateLdapProviderRuleUpdate,
 		Delete: identityProviderDelete,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			preferWriteOnlySecret("admin_password"),
+		},
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderLdap),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -29,7 +33,7 @@ func resourceAppgateLdapProvider() *schema.Resource {
 		},
 
 		Schema: func() map[string]*schema.Schema {
-			s := ldapProviderSchema()
+			s := mergeSchemaMaps(ldapProviderSchema(), identityProviderTestSchema(true))
 			s["type"].Default = identityProviderLdap
 
 			return s
@@ -115,8 +119,8 @@ func resourceAppgateLdapProviderRuleCreate(d *schema.ResourceData, meta interfac
 	if v, ok := d.GetOk("admin_distinguished_name"); ok {
 		args.SetAdminDistinguishedName(v.(string))
 	}
-	if v, ok := d.GetOk("admin_password"); ok {
-		args.SetAdminPassword(v.(string))
+	if v, ok := secretFromConfig(d, "admin_password"); ok {
+		args.SetAdminPassword(v)
 	}
 	if v, ok := d.GetOk("base_dn"); ok {
 		args.SetBaseDn(v.(string))
@@ -147,7 +151,10 @@ func resourceAppgateLdapProviderRuleCreate(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Could not create %s provider %w", identityProviderLdap, prettyPrintAPIError(err))
 	}
 	d.SetId(p.GetId())
-	return resourceAppgateLdapProviderRuleRead(d, meta)
+	if err := resourceAppgateLdapProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, args, true)
 }
 
 func resourceAppgateLdapProviderRuleRead(d *schema.ResourceData, meta interface{}) error {
@@ -168,6 +175,7 @@ func resourceAppgateLdapProviderRuleRead(d *schema.ResourceData, meta interface{
 		}
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	setUpdated(d, ldap.GetUpdated())
 	d.Set("type", identityProviderLdap)
 	// base attributes
 	d.Set("name", ldap.Name)
@@ -212,9 +220,6 @@ func resourceAppgateLdapProviderRuleRead(d *schema.ResourceData, meta interface{
 	d.Set("port", ldap.GetPort())
 	d.Set("ssl_enabled", ldap.GetSslEnabled())
 	d.Set("admin_distinguished_name", ldap.GetAdminDistinguishedName())
-	if val, ok := d.GetOk("admin_password"); ok {
-		d.Set("admin_password", val.(string))
-	}
 	if v, ok := ldap.GetBaseDnOk(); ok {
 		d.Set("base_dn", *v)
 	}
@@ -280,6 +285,9 @@ func resourceAppgateLdapProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if err != nil {
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateLdapProvider(), originalLdapProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalLdapProvider.SetName(d.Get("name").(string))
@@ -369,8 +377,9 @@ func resourceAppgateLdapProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if d.HasChange("admin_distinguished_name") {
 		originalLdapProvider.SetAdminDistinguishedName(d.Get("admin_distinguished_name").(string))
 	}
-	if d.HasChange("admin_password") {
-		originalLdapProvider.SetAdminPassword(d.Get("admin_password").(string))
+	if secretHasChange(d, "admin_password") {
+		v, _ := secretFromConfig(d, "admin_password")
+		originalLdapProvider.SetAdminPassword(v)
 	}
 	if d.HasChange("base_dn") {
 		originalLdapProvider.SetBaseDn(d.Get("base_dn").(string))
@@ -401,5 +410,8 @@ func resourceAppgateLdapProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if err != nil {
 		return fmt.Errorf("Could not update %s provider %w", identityProviderLdap, prettyPrintAPIError(err))
 	}
-	return resourceAppgateLdapProviderRuleRead(d, meta)
+	if err := resourceAppgateLdapProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, *originalLdapProvider, false)
 }
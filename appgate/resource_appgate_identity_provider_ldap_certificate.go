@@ -16,11 +16,15 @@ func resourceAppgateLdapCertificateProvider() *schema.Resource {
 		Read:   resourceAppgateLdapCertificateProviderRuleRead,
 		Update: resourceAppgateLdapCertificateProviderRuleUpdate,
 		Delete: identityProviderDelete,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			preferWriteOnlySecret("admin_password"),
+		},
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderLdapCertificate),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 		Schema: func() map[string]*schema.Schema {
-			s := ldapProviderSchema()
+			s := mergeSchemaMaps(ldapProviderSchema(), identityProviderTestSchema(true))
 			s["type"].Default = identityProviderLdapCertificate
 
 			s["ca_certificates"] = &schema.Schema{
@@ -139,8 +143,8 @@ func resourceAppgateLdapCertificateProviderRuleCreate(d *schema.ResourceData, me
 	if v, ok := d.GetOk("admin_distinguished_name"); ok {
 		args.SetAdminDistinguishedName(v.(string))
 	}
-	if v, ok := d.GetOk("admin_password"); ok {
-		args.SetAdminPassword(v.(string))
+	if v, ok := secretFromConfig(d, "admin_password"); ok {
+		args.SetAdminPassword(v)
 	}
 	if v, ok := d.GetOk("base_dn"); ok {
 		args.SetBaseDn(v.(string))
@@ -188,7 +192,10 @@ func resourceAppgateLdapCertificateProviderRuleCreate(d *schema.ResourceData, me
 		return fmt.Errorf("Could not create %s provider %w", identityProviderLdapCertificate, prettyPrintAPIError(err))
 	}
 	d.SetId(p.GetId())
-	return resourceAppgateLdapCertificateProviderRuleRead(d, meta)
+	if err := resourceAppgateLdapCertificateProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, args, true)
 }
 
 func resourceAppgateLdapCertificateProviderRuleRead(d *schema.ResourceData, meta interface{}) error {
@@ -209,6 +216,7 @@ func resourceAppgateLdapCertificateProviderRuleRead(d *schema.ResourceData, meta
 		}
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	setUpdated(d, ldap.GetUpdated())
 	d.Set("type", identityProviderLdapCertificate)
 	// base attributes
 	d.Set("name", ldap.GetName())
@@ -255,9 +263,6 @@ func resourceAppgateLdapCertificateProviderRuleRead(d *schema.ResourceData, meta
 	d.Set("port", ldap.GetPort())
 	d.Set("ssl_enabled", ldap.GetSslEnabled())
 	d.Set("admin_distinguished_name", ldap.GetAdminDistinguishedName())
-	if val, ok := d.GetOk("admin_password"); ok {
-		d.Set("admin_password", val.(string))
-	}
 	if v, ok := ldap.GetBaseDnOk(); ok {
 		d.Set("base_dn", *v)
 	}
@@ -297,6 +302,9 @@ func resourceAppgateLdapCertificateProviderRuleUpdate(d *schema.ResourceData, me
 	if err != nil {
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateLdapCertificateProvider(), originalLdapCertificateProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalLdapCertificateProvider.SetName(d.Get("name").(string))
@@ -394,8 +402,9 @@ func resourceAppgateLdapCertificateProviderRuleUpdate(d *schema.ResourceData, me
 	if d.HasChange("admin_distinguished_name") {
 		originalLdapCertificateProvider.SetAdminDistinguishedName(d.Get("admin_distinguished_name").(string))
 	}
-	if d.HasChange("admin_password") {
-		originalLdapCertificateProvider.SetAdminPassword(d.Get("admin_password").(string))
+	if secretHasChange(d, "admin_password") {
+		v, _ := secretFromConfig(d, "admin_password")
+		originalLdapCertificateProvider.SetAdminPassword(v)
 	}
 	if d.HasChange("base_dn") {
 		originalLdapCertificateProvider.SetBaseDn(d.Get("base_dn").(string))
@@ -446,5 +455,8 @@ func resourceAppgateLdapCertificateProviderRuleUpdate(d *schema.ResourceData, me
 	if err != nil {
 		return fmt.Errorf("Could not update %s provider %w", identityProviderLdapCertificate, prettyPrintAPIError(err))
 	}
-	return resourceAppgateLdapCertificateProviderRuleRead(d, meta)
+	if err := resourceAppgateLdapCertificateProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, *originalLdapCertificateProvider, false)
 }
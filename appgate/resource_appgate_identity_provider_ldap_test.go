@@ -2,6 +2,7 @@ package appgate
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -549,3 +550,75 @@ func testAccLdapIdentityProviderImportStateCheckFunc(expectedStates int) resourc
 		return nil
 	}
 }
+
+func TestAccLdapIdentityProviderClaimMappingPreset(t *testing.T) {
+	resourceName := "appgatesdp_ldap_identity_provider.ldap_preset"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLdapIdentityProviderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLdapIdentityProviderClaimMappingPreset(rName, "sAMAccountName"),
+				ExpectError: regexp.MustCompile(`claim_name "username" is mapped from both`),
+			},
+			{
+				Config: testAccCheckLdapIdentityProviderClaimMappingPreset(rName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLdapIdentityProviderExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "claim_mapping_preset", "active_directory"),
+					resource.TestCheckResourceAttr(resourceName, "claim_mappings.#", "7"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "claim_mappings.*", map[string]string{
+						"attribute_name": "userPrincipalName",
+						"claim_name":     "username",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "claim_mappings.*", map[string]string{
+						"attribute_name": "memberOf",
+						"claim_name":     "groups",
+						"list":           "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "claim_mappings.*", map[string]string{
+						"attribute_name": "department",
+						"claim_name":     "department",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLdapIdentityProviderClaimMappingPreset(rName, duplicate string) string {
+	var extra string
+	if len(duplicate) > 0 {
+		extra = fmt.Sprintf(`
+  claim_mappings {
+    attribute_name = "%s"
+    claim_name     = "username"
+  }`, duplicate)
+	}
+	return fmt.Sprintf(`
+resource "appgatesdp_ldap_identity_provider" "ldap_preset" {
+  name                     = "%s"
+  port                     = 389
+  admin_distinguished_name = "CN=admin,OU=Users,DC=company,DC=com"
+  hostnames                = ["dc.ad.company.com"]
+  ssl_enabled              = true
+  base_dn                  = "OU=Users,DC=company,DC=com"
+  object_class             = "user"
+  username_attribute       = "sAMAccountName"
+  membership_filter        = "(objectCategory=group)"
+  membership_base_dn       = "OU=Groups,DC=company,DC=com"
+  admin_password           = "helloworld"
+  claim_mapping_preset     = "active_directory"
+  claim_mappings {
+    attribute_name = "userPrincipalName"
+    claim_name     = "username"
+  }
+  claim_mappings {
+    attribute_name = "department"
+    claim_name     = "department"
+  }%s
+}
+`, rName, extra)
+}
@@ -12,10 +12,11 @@ import (
 
 func resourceAppgateLocalDatabaseProvider() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAppgateLocalDatabaseProviderRuleCreate,
-		Read:   resourceAppgateLocalDatabaseProviderRuleRead,
-		Update: resourceAppgateLocalDatabaseProviderRuleUpdate,
-		Delete: resourceAppgateLocalDatabaseProviderRuleDelete,
+		Create:        resourceAppgateLocalDatabaseProviderRuleCreate,
+		Read:          resourceAppgateLocalDatabaseProviderRuleRead,
+		Update:        resourceAppgateLocalDatabaseProviderRuleUpdate,
+		Delete:        resourceAppgateLocalDatabaseProviderRuleDelete,
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderLocalDatabase),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -98,6 +99,7 @@ func resourceAppgateLocalDatabaseProviderRuleRead(d *schema.ResourceData, meta i
 		d.SetId("")
 		return fmt.Errorf("Failed to read LocalDatabase Identity provider, %w", err)
 	}
+	setUpdated(d, localDatabase.GetUpdated())
 	d.SetId(localDatabase.GetId())
 
 	d.Set("type", identityProviderLocalDatabase)
@@ -155,6 +157,9 @@ func resourceAppgateLocalDatabaseProviderRuleUpdate(d *schema.ResourceData, meta
 	if err != nil {
 		return fmt.Errorf("Failed to read LocalDatabase Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateLocalDatabaseProvider(), originalLocalDatabaseProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalLocalDatabaseProvider.SetName(d.Get("name").(string))
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/go-cty/cty"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAppgateOidcProvider() *schema.Resource {
@@ -16,6 +18,13 @@ func resourceAppgateOidcProvider() *schema.Resource {
 		Read:   resourceAppgateOidcProviderRuleRead,
 		Update: resourceAppgateOidcProviderRuleUpdate,
 		Delete: identityProviderDelete,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validation.PreferWriteOnlyAttribute(
+				cty.GetAttrPath("google").Index(cty.UnknownVal(cty.Number)).GetAttr("client_secret"),
+				cty.GetAttrPath("google_client_secret_wo"),
+			),
+		},
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderOidc),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -27,7 +36,7 @@ func resourceAppgateOidcProvider() *schema.Resource {
 		},
 
 		Schema: func() map[string]*schema.Schema {
-			s := identityProviderSchema()
+			s := mergeSchemaMaps(identityProviderSchema(), claimMappingPresetSchema())
 			s["type"].Default = identityProviderOidc
 
 			s["issuer"] = &schema.Schema{
@@ -71,6 +80,9 @@ func resourceAppgateOidcProvider() *schema.Resource {
 					},
 				},
 			}
+			for k, v := range writeOnlySecretSchema("google_client_secret", "google.0.client_secret", "Client secret of the Google OAuth client.") {
+				s[k] = v
+			}
 			return s
 		}(),
 	}
@@ -167,6 +179,9 @@ func resourceAppgateOidcProviderRuleCreate(d *schema.ResourceData, meta interfac
 				g.SetRefreshToken(v.(bool))
 			}
 		}
+		if v, ok := writeOnlyFromConfig(d, "google_client_secret_wo"); ok {
+			g.SetClientSecret(v)
+		}
 		args.SetGoogle(*g)
 	}
 
@@ -194,6 +209,7 @@ func resourceAppgateOidcProviderRuleRead(d *schema.ResourceData, meta interface{
 		d.SetId("")
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	setUpdated(d, oidc.GetUpdated())
 	d.Set("type", identityProviderOidc)
 	// base attributes
 	d.Set("name", oidc.Name)
@@ -252,11 +268,8 @@ func resourceAppgateOidcProviderRuleRead(d *schema.ResourceData, meta interface{
 		if val, ok := v.GetEnabledOk(); ok {
 			g["enabled"] = *val
 		}
-		if val, ok := v.GetClientSecretOk(); ok {
-			g["client_secret"] = *val
-		} else {
-			g["client_secret"] = d.Get("google.0.client_secret")
-		}
+		// the secret is not read back, so a secret set with google_client_secret_wo never ends up in the state.
+		g["client_secret"] = d.Get("google.0.client_secret")
 		if val, ok := v.GetRefreshTokenOk(); ok {
 			g["refresh_token"] = *val
 		}
@@ -279,6 +292,9 @@ func resourceAppgateOidcProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if err != nil {
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateOidcProvider(), originalOidcProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalOidcProvider.SetName(d.Get("name").(string))
@@ -372,10 +388,15 @@ func resourceAppgateOidcProviderRuleUpdate(d *schema.ResourceData, meta interfac
 		originalOidcProvider.SetAudience(v.(string))
 	}
 
-	if d.HasChange("google") {
+	if d.HasChanges("google", "google_client_secret_wo_version") {
 		_, v := d.GetChange("google")
 		googles := readOidcProviderGoogleFromConfig(v.([]interface{}))
-		originalOidcProvider.SetGoogle(googles[0])
+		if len(googles) > 0 {
+			if v, ok := writeOnlyFromConfig(d, "google_client_secret_wo"); ok {
+				googles[0].SetClientSecret(v)
+			}
+			originalOidcProvider.SetGoogle(googles[0])
+		}
 	}
 
 	req := api.IdentityProvidersIdPut(ctx, d.Id())
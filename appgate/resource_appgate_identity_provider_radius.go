@@ -16,6 +16,10 @@ func resourceAppgateRadiusProvider() *schema.Resource {
 		Read:   resourceAppgateRadiusProviderRuleRead,
 		Update: resourceAppgateRadiusProviderRuleUpdate,
 		Delete: identityProviderDelete,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			preferWriteOnlySecret("shared_secret"),
+		},
+		CustomizeDiff: identityProviderCustomizeDiff(identityProviderRadius),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -27,7 +31,7 @@ func resourceAppgateRadiusProvider() *schema.Resource {
 		},
 
 		Schema: func() map[string]*schema.Schema {
-			s := identityProviderSchema()
+			s := mergeSchemaMaps(identityProviderSchema(), identityProviderTestSchema(true))
 			s["type"].Default = identityProviderRadius
 
 			s["hostnames"] = &schema.Schema{
@@ -40,9 +44,13 @@ func resourceAppgateRadiusProvider() *schema.Resource {
 				Required: true,
 			}
 			s["shared_secret"] = &schema.Schema{
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"shared_secret", "shared_secret_wo"},
+			}
+			for k, v := range writeOnlySecretSchema("shared_secret", "shared_secret", "Radius shared secret to authenticate to the server.") {
+				s[k] = v
 			}
 			s["authentication_protocol"] = &schema.Schema{
 				Type:     schema.TypeString,
@@ -143,8 +151,8 @@ func resourceAppgateRadiusProviderRuleCreate(d *schema.ResourceData, meta interf
 	if v, ok := d.GetOk("port"); ok {
 		args.SetPort(int32(v.(int)))
 	}
-	if v, ok := d.GetOk("shared_secret"); ok {
-		args.SetSharedSecret(v.(string))
+	if v, ok := secretFromConfig(d, "shared_secret"); ok {
+		args.SetSharedSecret(v)
 	}
 	if v, ok := d.GetOk("authentication_protocol"); ok {
 		args.SetAuthenticationProtocol(v.(string))
@@ -156,7 +164,10 @@ func resourceAppgateRadiusProviderRuleCreate(d *schema.ResourceData, meta interf
 		return fmt.Errorf("Could not create %s provider %w", identityProviderRadius, prettyPrintAPIError(err))
 	}
 	d.SetId(p.GetId())
-	return resourceAppgateRadiusProviderRuleRead(d, meta)
+	if err := resourceAppgateRadiusProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, args, true)
 }
 
 func resourceAppgateRadiusProviderRuleRead(d *schema.ResourceData, meta interface{}) error {
@@ -174,6 +185,7 @@ func resourceAppgateRadiusProviderRuleRead(d *schema.ResourceData, meta interfac
 		d.SetId("")
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	setUpdated(d, radius.GetUpdated())
 	d.Set("type", identityProviderRadius)
 	// base attributes
 	d.Set("name", radius.Name)
@@ -224,9 +236,6 @@ func resourceAppgateRadiusProviderRuleRead(d *schema.ResourceData, meta interfac
 	if v, ok := radius.GetPortOk(); ok {
 		d.Set("port", *v)
 	}
-	if val, ok := d.GetOk("shared_secret"); ok {
-		d.Set("shared_secret", val)
-	}
 	if v, ok := radius.GetAuthenticationProtocolOk(); ok {
 		d.Set("authentication_protocol", *v)
 	}
@@ -246,6 +255,9 @@ func resourceAppgateRadiusProviderRuleUpdate(d *schema.ResourceData, meta interf
 	if err != nil {
 		return fmt.Errorf("Failed to read LDAP Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateRadiusProvider(), originalRadiusProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalRadiusProvider.SetName(d.Get("name").(string))
@@ -339,7 +351,10 @@ func resourceAppgateRadiusProviderRuleUpdate(d *schema.ResourceData, meta interf
 		originalRadiusProvider.SetPort(int32(v.(int)))
 	}
 
-	originalRadiusProvider.SetSharedSecret(d.Get("shared_secret").(string))
+	// the shared secret is not returned by the controller, and is required on every update.
+	if v, ok := secretFromConfig(d, "shared_secret"); ok {
+		originalRadiusProvider.SetSharedSecret(v)
+	}
 
 	req := api.IdentityProvidersIdPut(ctx, d.Id())
 	req = req.Body(*originalRadiusProvider)
@@ -347,5 +362,8 @@ func resourceAppgateRadiusProviderRuleUpdate(d *schema.ResourceData, meta interf
 	if err != nil {
 		return fmt.Errorf("Could not update %s provider %w", identityProviderRadius, prettyPrintAPIError(err))
 	}
-	return resourceAppgateRadiusProviderRuleRead(d, meta)
+	if err := resourceAppgateRadiusProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, *originalRadiusProvider, false)
 }
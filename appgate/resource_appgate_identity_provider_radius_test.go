@@ -2,6 +2,7 @@ package appgate
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -238,3 +239,58 @@ resource "appgatesdp_radius_identity_provider" "radius_test_resource" {
 }
 `, rName)
 }
+
+func TestAccRadiusIdentityProviderReferencesByName(t *testing.T) {
+	resourceName := "appgatesdp_radius_identity_provider.radius_names"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRadiusIdentityProviderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckRadiusIdentityProviderReferencesByName(rName, "default pool v6", "default pool v6"),
+				ExpectError: regexp.MustCompile(`ip_pool_v4: IP pool "default pool v6" is an IPv6 pool`),
+			},
+			{
+				Config:      testAccCheckRadiusIdentityProviderReferencesByName(rName, "no such pool", "default pool v6"),
+				ExpectError: regexp.MustCompile(`ip_pool_v4: IP pool "no such pool" does not exist`),
+			},
+			{
+				Config: testAccCheckRadiusIdentityProviderReferencesByName(rName, "default pool v4", "default pool v6"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRadiusIdentityProviderExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "ip_pool_v4", "data.appgatesdp_ip_pool.ip_v4_pool", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "ip_pool_v6", "data.appgatesdp_ip_pool.ip_v6_pool", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "on_boarding_two_factor.0.mfa_provider_id", "data.appgatesdp_mfa_provider.fido", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRadiusIdentityProviderReferencesByName(rName, ipv4, ipv6 string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_ip_pool" "ip_v4_pool" {
+  ip_pool_name = "default pool v4"
+}
+data "appgatesdp_ip_pool" "ip_v6_pool" {
+  ip_pool_name = "default pool v6"
+}
+data "appgatesdp_mfa_provider" "fido" {
+  mfa_provider_name = "Default FIDO2 Provider"
+}
+resource "appgatesdp_radius_identity_provider" "radius_names" {
+  name          = "%s"
+  hostnames     = ["radius.company.com"]
+  port          = 1812
+  shared_secret = "hunter2"
+  ip_pool_v4    = "%s"
+  ip_pool_v6    = "%s"
+  on_boarding_two_factor {
+    mfa_provider_id = "Default FIDO2 Provider"
+    message         = "welcome"
+  }
+}
+`, rName, ipv4, ipv6)
+}
@@ -1,12 +1,15 @@
 package appgate
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -16,8 +19,12 @@ func resourceAppgateSamlProvider() *schema.Resource {
 		Read:   resourceAppgateSamlProviderRuleRead,
 		Update: resourceAppgateSamlProviderRuleUpdate,
 		Delete: identityProviderDelete,
+		CustomizeDiff: customdiff.All(
+			resourceAppgateSamlProviderCustomizeDiff,
+			identityProviderCustomizeDiff(identityProviderSaml),
+		),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAppgateSamlProviderImport,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -27,16 +34,31 @@ func resourceAppgateSamlProvider() *schema.Resource {
 		},
 
 		Schema: func() map[string]*schema.Schema {
-			s := identityProviderSchema()
+			s := mergeSchemaMaps(identityProviderSchema(), claimMappingPresetSchema(), identityProviderTestSchema(false))
 			s["type"].Default = identityProviderSaml
 
+			s["metadata_xml"] = &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "SAML metadata of the identity provider, used to populate redirect_url, issuer and provider_certificate.",
+				Optional:         true,
+				ConflictsWith:    []string{"redirect_url", "issuer", "provider_certificate"},
+				ValidateDiagFunc: validateSAMLMetadata,
+			}
+			s["fail_on_certificate_change"] = &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Fail the plan if the signing certificate in metadata_xml differs from the provider_certificate on the controller. Set it to false to rotate the certificate.",
+				Optional:    true,
+				Default:     true,
+			}
 			s["redirect_url"] = &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			}
 			s["issuer"] = &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			}
 			s["audience"] = &schema.Schema{
 				Type:     schema.TypeString,
@@ -44,7 +66,8 @@ func resourceAppgateSamlProvider() *schema.Resource {
 			}
 			s["provider_certificate"] = &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			}
 			s["decryption_key"] = &schema.Schema{
 				Type:     schema.TypeString,
@@ -59,6 +82,64 @@ func resourceAppgateSamlProvider() *schema.Resource {
 	}
 }
 
+// resourceAppgateSamlProviderCustomizeDiff populates redirect_url, issuer and provider_certificate from
+// metadata_xml. A rotated signing certificate fails the plan, unless fail_on_certificate_change is false,
+// since a CustomizeDiff can not return a warning. Then it shows up as a provider_certificate change.
+func resourceAppgateSamlProviderCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("metadata_xml") {
+		for _, k := range []string{"redirect_url", "issuer", "provider_certificate"} {
+			if err := d.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v, ok := d.GetOk("metadata_xml")
+	if !ok {
+		raw := d.GetRawConfig()
+		if raw.IsNull() || !raw.IsKnown() {
+			return nil
+		}
+		var missing []error
+		for _, k := range []string{"redirect_url", "issuer", "provider_certificate"} {
+			if raw.GetAttr(k).IsNull() {
+				missing = append(missing, fmt.Errorf("%s is required, unless metadata_xml is set", k))
+			}
+		}
+		return errors.Join(missing...)
+	}
+	md, err := parseSAMLMetadata([]byte(v.(string)), time.Now())
+	if err != nil {
+		return err
+	}
+	if d.Get("redirect_url").(string) != md.RedirectURL {
+		if err := d.SetNew("redirect_url", md.RedirectURL); err != nil {
+			return err
+		}
+	}
+	if d.Get("issuer").(string) != md.Issuer {
+		if err := d.SetNew("issuer", md.Issuer); err != nil {
+			return err
+		}
+	}
+	if current := d.Get("provider_certificate").(string); !samePEMCertificate(current, md.Certificate) {
+		if len(current) > 0 && d.Get("fail_on_certificate_change").(bool) {
+			return fmt.Errorf("the signing certificate in metadata_xml differs from the provider_certificate on the controller for %s, set fail_on_certificate_change to false to rotate it", md.Issuer)
+		}
+		if err := d.SetNew("provider_certificate", md.Certificate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceAppgateSamlProviderImport sets fail_on_certificate_change to its default,
+// since it is not stored in the Controller.
+func resourceAppgateSamlProviderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("fail_on_certificate_change", true)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceAppgateSamlProviderRuleCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Creating SamlProvider: %s", d.Get("name").(string))
 	token, err := meta.(*Client).GetToken()
@@ -149,7 +230,10 @@ func resourceAppgateSamlProviderRuleCreate(d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Could not create %s provider %w", identityProviderSaml, prettyPrintAPIError(err))
 	}
 	d.SetId(p.GetId())
-	return resourceAppgateSamlProviderRuleRead(d, meta)
+	if err := resourceAppgateSamlProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, args, true)
 }
 
 func resourceAppgateSamlProviderRuleRead(d *schema.ResourceData, meta interface{}) error {
@@ -167,6 +251,7 @@ func resourceAppgateSamlProviderRuleRead(d *schema.ResourceData, meta interface{
 		d.SetId("")
 		return fmt.Errorf("Failed to read Saml Identity provider, %w", err)
 	}
+	setUpdated(d, saml.GetUpdated())
 	d.Set("type", identityProviderSaml)
 	// base attributes
 	d.Set("name", saml.GetName())
@@ -229,6 +314,9 @@ func resourceAppgateSamlProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if err != nil {
 		return fmt.Errorf("Failed to read Saml Identity provider, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateSamlProvider(), originalSamlProvider.GetUpdated()); err != nil {
+		return err
+	}
 	// base attributes
 	if d.HasChange("name") {
 		originalSamlProvider.SetName(d.Get("name").(string))
@@ -334,5 +422,8 @@ func resourceAppgateSamlProviderRuleUpdate(d *schema.ResourceData, meta interfac
 	if err != nil {
 		return fmt.Errorf("Could not update %s provider %w", identityProviderSaml, prettyPrintAPIError(err))
 	}
-	return resourceAppgateSamlProviderRuleRead(d, meta)
+	if err := resourceAppgateSamlProviderRuleRead(d, meta); err != nil {
+		return err
+	}
+	return testIdentityProviderOnApply(d, meta, *originalSamlProvider, false)
 }
@@ -19,7 +19,7 @@ func resourceAppgateIPPool() *schema.Resource {
 		Update: resourceAppgateIPPoolUpdate,
 		Delete: resourceAppgateIPPoolDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importPreventDestroyIfReferenced,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -48,6 +48,10 @@ func resourceAppgateIPPool() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
+			"prevent_destroy_if_referenced": preventDestroyIfReferencedSchema(),
+
 			"ip_version6": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -156,6 +160,7 @@ func resourceAppgateIPPoolRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		return fmt.Errorf("Failed to read Ip pool, %w", err)
 	}
+	setUpdated(d, IPPool.GetUpdated())
 	d.SetId(IPPool.GetId())
 	d.Set("ip_pool_id", IPPool.GetId())
 	d.Set("name", IPPool.GetName())
@@ -207,6 +212,9 @@ func resourceAppgateIPPoolUpdate(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return fmt.Errorf("Failed to read Ip pool while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateIPPool(), originalIPPool.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalIPPool.SetName(d.Get("name").(string))
@@ -261,6 +269,9 @@ func resourceAppgateIPPoolDelete(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
+	if err := checkReferencesBeforeDelete(BaseAuthContext(token), d, meta, "Ip pool", referenceQuery{ID: d.Id()}); err != nil {
+		return err
+	}
 	api := meta.(*Client).API.IPPoolsApi
 	if _, err := api.IpPoolsIdDelete(BaseAuthContext(token), d.Id()).Execute(); err != nil {
 		return fmt.Errorf("Could not delete Ip pool %w", prettyPrintAPIError(err))
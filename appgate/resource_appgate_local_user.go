@@ -148,6 +148,7 @@ func resourceAppgateLocalUserRead(ctx context.Context, d *schema.ResourceData, m
 		}
 		return diag.FromErr(prettyPrintAPIError(err))
 	}
+	setUpdated(d, localUser.GetUpdated())
 	d.SetId(localUser.GetId())
 	d.Set("local_user_id", localUser.GetId())
 	d.Set("name", localUser.GetName())
@@ -178,6 +179,9 @@ func resourceAppgateLocalUserUpdate(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.FromErr(prettyPrintAPIError(err))
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateLocalUser(), user.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		user.SetName(d.Get("name").(string))
@@ -0,0 +1,613 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceAppgateLocalUsers manages a list of users in the local database identity provider.
+// Users that are not in the list, such as users from appgatesdp_local_user, are left as they are.
+func resourceAppgateLocalUsers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateLocalUsersCreate,
+		ReadContext:   resourceAppgateLocalUsersRead,
+		UpdateContext: resourceAppgateLocalUsersUpdate,
+		DeleteContext: resourceAppgateLocalUsersDelete,
+		CustomizeDiff: resourceAppgateLocalUsersCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:        schema.TypeList,
+				Description: "Users in the local database, identified by name.",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Description:  "Username, must be unique in the list.",
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"first_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"last_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"phone": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"notes": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"tags": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Description: "Password of the user. Required when the user is created, unless password_wo is set.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"password_wo": {
+							Type:        schema.TypeString,
+							Description: "Write-only password of the user, the value is not stored in the state. Requires Terraform 1.11 or later.",
+							Optional:    true,
+							Sensitive:   true,
+							WriteOnly:   true,
+						},
+						"password_wo_version": {
+							Type:        schema.TypeInt,
+							Description: "Version of password_wo, change it to update the password on the controller.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"batch_size": {
+				Type:         schema.TypeInt,
+				Description:  "Number of users created, updated or deleted at the same time.",
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"unlock_trigger": {
+				Type:        schema.TypeString,
+				Description: "Change this value to unlock all users in locked_users.",
+				Optional:    true,
+			},
+			"user_ids": {
+				Type:        schema.TypeMap,
+				Description: "IDs of the users, by name.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"locked_users": {
+				Type:        schema.TypeList,
+				Description: "Names of the users that are locked out after too many failed login attempts.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// localUserSpec is one user block in appgatesdp_local_users.
+type localUserSpec struct {
+	index           int
+	name            string
+	firstName       string
+	lastName        string
+	email           string
+	phone           string
+	notes           string
+	tags            []string
+	password        string
+	passwordVersion int
+}
+
+// localUserOp is the request for one user, a user without id is created.
+type localUserOp struct {
+	spec     localUserSpec
+	id       string
+	password string
+	unlock   bool
+}
+
+func expandLocalUserSpecs(users []interface{}) map[string]localUserSpec {
+	specs := make(map[string]localUserSpec, len(users))
+	for i, raw := range users {
+		u, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		spec := localUserSpec{
+			index:           i,
+			name:            u["name"].(string),
+			firstName:       u["first_name"].(string),
+			lastName:        u["last_name"].(string),
+			email:           u["email"].(string),
+			phone:           u["phone"].(string),
+			notes:           u["notes"].(string),
+			tags:            make([]string, 0),
+			password:        u["password"].(string),
+			passwordVersion: u["password_wo_version"].(int),
+		}
+		if v, ok := u["tags"].(*schema.Set); ok {
+			for _, t := range v.List() {
+				spec.tags = append(spec.tags, t.(string))
+			}
+			sort.Strings(spec.tags)
+		}
+		specs[spec.name] = spec
+	}
+	return specs
+}
+
+// sameLocalUserProfile returns true if the attributes stored on the controller, apart from the password, are equal.
+func sameLocalUserProfile(a, b localUserSpec) bool {
+	return a.firstName == b.firstName &&
+		a.lastName == b.lastName &&
+		a.email == b.email &&
+		a.phone == b.phone &&
+		a.notes == b.notes &&
+		reflect.DeepEqual(a.tags, b.tags)
+}
+
+// diffLocalUsers returns the sorted names of the users to create, update and delete.
+// ids is the users created by the resource, and locked the users to unlock.
+func diffLocalUsers(old, new map[string]localUserSpec, ids map[string]string, locked map[string]bool) (create, update, remove []string) {
+	create, update, remove = make([]string, 0), make([]string, 0), make([]string, 0)
+	for name, spec := range new {
+		if _, ok := ids[name]; !ok {
+			create = append(create, name)
+			continue
+		}
+		prev, ok := old[name]
+		if !ok || !sameLocalUserProfile(prev, spec) || prev.password != spec.password ||
+			prev.passwordVersion != spec.passwordVersion || locked[name] {
+			update = append(update, name)
+		}
+	}
+	for name := range ids {
+		if _, ok := new[name]; !ok {
+			remove = append(remove, name)
+		}
+	}
+	sort.Strings(create)
+	sort.Strings(update)
+	sort.Strings(remove)
+	return create, update, remove
+}
+
+// inBatches calls fn for the n items, batchSize items at the same time.
+// It stops after the first batch with an error, and the remaining items are not called.
+func inBatches(n, batchSize int, fn func(i int) error) error {
+	for i := 0; i < n; i += batchSize {
+		end := i + batchSize
+		if end > n {
+			end = n
+		}
+		errs := make([]error, end-i)
+		var wg sync.WaitGroup
+		for j := i; j < end; j++ {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				errs[j-i] = fn(j)
+			}(j)
+		}
+		wg.Wait()
+
+		failed := make([]string, 0)
+		for _, err := range errs {
+			if err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("stopped after %d of %d users:\n%s", i, n, strings.Join(failed, "\n"))
+		}
+	}
+	return nil
+}
+
+// localUserPassword returns the password, or the write-only password, of the user.
+func localUserPassword(d *schema.ResourceData, spec localUserSpec) string {
+	if len(spec.password) > 0 {
+		return spec.password
+	}
+	v, _ := writeOnlyFromConfigAt(d, cty.GetAttrPath("user").IndexInt(spec.index).GetAttr("password_wo"))
+	return v
+}
+
+// listLocalUsers returns all local users, a page at a time.
+func listLocalUsers(ctx context.Context, api *openapi.LocalUsersApiService) ([]openapi.LocalUser, error) {
+	users, err := listAllPages(func(pageRange string) ([]openapi.LocalUser, error) {
+		list, _, err := api.LocalUsersGet(ctx).OrderBy("name").Range_(pageRange).Execute()
+		return list.GetData(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list local users, %w", prettyPrintAPIError(err))
+	}
+	return users, nil
+}
+
+func localUserIDsByName(ctx context.Context, api *openapi.LocalUsersApiService) (map[string]string, error) {
+	users, err := listLocalUsers(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(users))
+	for _, u := range users {
+		ids[u.GetName()] = u.GetId()
+	}
+	return ids, nil
+}
+
+func putLocalUser(ctx context.Context, api *openapi.LocalUsersApiService, op localUserOp) (string, error) {
+	if len(op.id) == 0 {
+		if len(op.password) == 0 {
+			return "", fmt.Errorf("password or password_wo is required to create local user %s", op.spec.name)
+		}
+		args := openapi.LocalUsersGetRequest{}
+		args.SetName(op.spec.name)
+		args.SetFirstName(op.spec.firstName)
+		args.SetLastName(op.spec.lastName)
+		args.SetEmail(op.spec.email)
+		args.SetPhone(op.spec.phone)
+		args.SetNotes(op.spec.notes)
+		args.SetTags(op.spec.tags)
+		args.SetPassword(op.password)
+		user, _, err := api.LocalUsersPost(ctx).LocalUsersGetRequest(args).Execute()
+		if err != nil {
+			return "", fmt.Errorf("could not create local user %s %w", op.spec.name, prettyPrintAPIError(err))
+		}
+		return user.GetId(), nil
+	}
+
+	user, _, err := api.LocalUsersIdGet(ctx, op.id).Execute()
+	if err != nil {
+		return "", fmt.Errorf("could not read local user %s %w", op.spec.name, prettyPrintAPIError(err))
+	}
+	user.SetFirstName(op.spec.firstName)
+	user.SetLastName(op.spec.lastName)
+	user.SetEmail(op.spec.email)
+	user.SetPhone(op.spec.phone)
+	user.SetNotes(op.spec.notes)
+	user.SetTags(op.spec.tags)
+	if len(op.password) > 0 {
+		user.SetPassword(op.password)
+	}
+	if op.unlock {
+		// the controller lifts the lock when the failed login attempts are reset.
+		user.SetFailedLoginAttempts(0)
+	}
+	if _, _, err := api.LocalUsersIdPut(ctx, op.id).LocalUser(*user).Execute(); err != nil {
+		return "", fmt.Errorf("could not update local user %s %w", op.spec.name, prettyPrintAPIError(err))
+	}
+	return op.id, nil
+}
+
+// applyLocalUsers runs ops in batches and adds the IDs of the users to ids, also when some of them fail.
+func applyLocalUsers(ctx context.Context, api *openapi.LocalUsersApiService, ops []localUserOp, batchSize int, ids map[string]string) error {
+	results := make([]string, len(ops))
+	err := inBatches(len(ops), batchSize, func(i int) error {
+		log.Printf("[DEBUG] Applying local user %s", ops[i].spec.name)
+		id, err := putLocalUser(ctx, api, ops[i])
+		results[i] = id
+		return err
+	})
+	for i, op := range ops {
+		if len(results[i]) > 0 {
+			ids[op.spec.name] = results[i]
+		}
+	}
+	return err
+}
+
+func setLocalUserIDs(d *schema.ResourceData, ids map[string]string) {
+	m := make(map[string]interface{}, len(ids))
+	for k, v := range ids {
+		m[k] = v
+	}
+	d.Set("user_ids", m)
+}
+
+func resourceAppgateLocalUsersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.LocalUsersApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	existing, err := localUserIDsByName(ctx, api)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	specs := expandLocalUserSpecs(d.Get("user").([]interface{}))
+	create, _, _ := diffLocalUsers(nil, specs, nil, nil)
+	log.Printf("[DEBUG] Creating %d local users", len(create))
+	ops := make([]localUserOp, 0, len(create))
+	for _, name := range create {
+		if _, ok := existing[name]; ok {
+			return diag.Errorf("local user %s already exists, remove it from the list or import it with appgatesdp_local_user", name)
+		}
+		ops = append(ops, localUserOp{spec: specs[name], password: localUserPassword(d, specs[name])})
+	}
+
+	d.SetId(uuid.New().String())
+	ids := make(map[string]string, len(ops))
+	err = applyLocalUsers(ctx, api, ops, d.Get("batch_size").(int), ids)
+	setLocalUserIDs(d, ids)
+	if err != nil {
+		return diag.Errorf("Failed to create local users, %s", err)
+	}
+	return resourceAppgateLocalUsersRead(ctx, d, meta)
+}
+
+func resourceAppgateLocalUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading local users: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.LocalUsersApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	list, err := listLocalUsers(ctx, api)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	byID := make(map[string]openapi.LocalUser, len(list))
+	for _, u := range list {
+		byID[u.GetId()] = u
+	}
+
+	ids := d.Get("user_ids").(map[string]interface{})
+	users := make([]interface{}, 0)
+	found := make(map[string]string)
+	locked := make([]string, 0)
+	for _, raw := range d.Get("user").([]interface{}) {
+		u := raw.(map[string]interface{})
+		name := u["name"].(string)
+		id, _ := ids[name].(string)
+		user, ok := byID[id]
+		if !ok {
+			// deleted outside of terraform, the next plan creates the user again.
+			log.Printf("[WARN] Local user %s (%s) not found, removing from state", name, id)
+			continue
+		}
+		u["first_name"] = user.GetFirstName()
+		u["last_name"] = user.GetLastName()
+		u["email"] = user.GetEmail()
+		u["phone"] = user.GetPhone()
+		u["notes"] = user.GetNotes()
+		u["tags"] = user.GetTags()
+		users = append(users, u)
+		found[name] = id
+		if _, ok := user.GetLockStartOk(); ok && user.GetFailedLoginAttempts() > 0 {
+			locked = append(locked, name)
+		}
+	}
+	if err := d.Set("user", users); err != nil {
+		return diag.FromErr(err)
+	}
+	setLocalUserIDs(d, found)
+	d.Set("locked_users", locked)
+	return nil
+}
+
+func resourceAppgateLocalUsersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.LocalUsersApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	o, n := d.GetChange("user")
+	oldSpecs := expandLocalUserSpecs(o.([]interface{}))
+	newSpecs := expandLocalUserSpecs(n.([]interface{}))
+	// user_ids is unknown in the plan when users are added or removed, so use the prior state.
+	oldIDs, _ := d.GetChange("user_ids")
+	ids := make(map[string]string)
+	for k, v := range oldIDs.(map[string]interface{}) {
+		ids[k] = v.(string)
+	}
+	locked := make(map[string]bool)
+	if d.HasChange("unlock_trigger") {
+		oldLocked, _ := d.GetChange("locked_users")
+		for _, name := range oldLocked.([]interface{}) {
+			locked[name.(string)] = true
+		}
+	}
+	create, update, remove := diffLocalUsers(oldSpecs, newSpecs, ids, locked)
+	log.Printf("[DEBUG] Local users to create %v, update %v and delete %v", create, update, remove)
+	batchSize := d.Get("batch_size").(int)
+
+	// delete first, so a user can be removed and added again with another letter case.
+	// user_ids is set before every return from here on, since it is unknown in the plan.
+	err = removeLocalUsers(remove, batchSize, ids, func(id, name string) error {
+		return deleteLocalUser(ctx, api, id, name)
+	})
+	if err != nil {
+		setLocalUserIDs(d, ids)
+		return diag.Errorf("Failed to delete local users, %s", err)
+	}
+
+	ops := make([]localUserOp, 0, len(create)+len(update))
+	if len(create) > 0 {
+		existing, err := localUserIDsByName(ctx, api)
+		if err != nil {
+			setLocalUserIDs(d, ids)
+			return diag.FromErr(err)
+		}
+		for _, name := range create {
+			if _, ok := existing[name]; ok {
+				setLocalUserIDs(d, ids)
+				return diag.Errorf("local user %s already exists, remove it from the list or import it with appgatesdp_local_user", name)
+			}
+			ops = append(ops, localUserOp{spec: newSpecs[name], password: localUserPassword(d, newSpecs[name])})
+		}
+	}
+	for _, name := range update {
+		op := localUserOp{spec: newSpecs[name], id: ids[name], unlock: locked[name]}
+		prev := oldSpecs[name]
+		if prev.password != op.spec.password || prev.passwordVersion != op.spec.passwordVersion {
+			op.password = localUserPassword(d, op.spec)
+		}
+		ops = append(ops, op)
+	}
+	err = applyLocalUsers(ctx, api, ops, batchSize, ids)
+	setLocalUserIDs(d, ids)
+	if err != nil {
+		return diag.Errorf("Failed to update local users, %s", err)
+	}
+	return resourceAppgateLocalUsersRead(ctx, d, meta)
+}
+
+// removeLocalUsers deletes the users in names in batches, and removes the deleted users from ids.
+// Users that failed, or were not attempted after a failed batch, are kept in ids.
+func removeLocalUsers(names []string, batchSize int, ids map[string]string, del func(id, name string) error) error {
+	deleted := make([]bool, len(names))
+	err := inBatches(len(names), batchSize, func(i int) error {
+		if err := del(ids[names[i]], names[i]); err != nil {
+			return err
+		}
+		deleted[i] = true
+		return nil
+	})
+	for i, name := range names {
+		if deleted[i] {
+			delete(ids, name)
+		}
+	}
+	return err
+}
+
+func deleteLocalUser(ctx context.Context, api *openapi.LocalUsersApiService, id, name string) error {
+	log.Printf("[DEBUG] Deleting local user %s (%s)", name, id)
+	response, err := api.LocalUsersIdDelete(ctx, id).Execute()
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("could not delete local user %s %w", name, prettyPrintAPIError(err))
+	}
+	return nil
+}
+
+func resourceAppgateLocalUsersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting local users: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.LocalUsersApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	ids := make(map[string]string)
+	names := make([]string, 0)
+	for k, v := range d.Get("user_ids").(map[string]interface{}) {
+		ids[k] = v.(string)
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	err = inBatches(len(names), d.Get("batch_size").(int), func(i int) error {
+		return deleteLocalUser(ctx, api, ids[names[i]], names[i])
+	})
+	if err != nil {
+		return diag.Errorf("Failed to delete local users, %s", err)
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceAppgateLocalUsersCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	names := make(map[string]bool)
+	for _, raw := range diff.Get("user").([]interface{}) {
+		u, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := u["name"].(string)
+		if len(name) == 0 {
+			continue
+		}
+		if names[name] {
+			return fmt.Errorf("local user %s is in the user list more than once", name)
+		}
+		names[name] = true
+	}
+
+	if config := diff.GetRawConfig(); config.IsKnown() && !config.IsNull() {
+		users := config.GetAttr("user")
+		if users.IsKnown() && !users.IsNull() {
+			for it := users.ElementIterator(); it.Next(); {
+				_, u := it.Element()
+				if !u.IsKnown() || u.IsNull() {
+					continue
+				}
+				if !u.GetAttr("password").IsNull() && !u.GetAttr("password_wo").IsNull() {
+					return fmt.Errorf("only one of password or password_wo can be set for a local user")
+				}
+			}
+		}
+	}
+
+	if diff.HasChange("user") {
+		o, n := diff.GetChange("user")
+		oldSpecs, newSpecs := expandLocalUserSpecs(o.([]interface{})), expandLocalUserSpecs(n.([]interface{}))
+		create, _, remove := diffLocalUsers(oldSpecs, newSpecs, localUserNames(oldSpecs), nil)
+		if len(create) > 0 || len(remove) > 0 {
+			if err := diff.SetNewComputed("user_ids"); err != nil {
+				return err
+			}
+		}
+	}
+	if diff.HasChange("unlock_trigger") {
+		return diff.SetNewComputed("locked_users")
+	}
+	return nil
+}
+
+// localUserNames returns the names in specs, in the same form as user_ids.
+func localUserNames(specs map[string]localUserSpec) map[string]string {
+	names := make(map[string]string, len(specs))
+	for name := range specs {
+		names[name] = name
+	}
+	return names
+}
@@ -0,0 +1,279 @@
+package appgate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccLocalUsersBasic(t *testing.T) {
+	resourceName := "appgatesdp_local_users.contractors"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLocalUsersDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLocalUsersBasic(rName, []string{"alice", "bob", "carol"}, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLocalUsersExist(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "user.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "user_ids.%", "3"),
+					resource.TestCheckResourceAttr(resourceName, "user.0.name", rName+"-alice"),
+					resource.TestCheckResourceAttr(resourceName, "user.0.first_name", "alice"),
+					resource.TestCheckResourceAttr(resourceName, "user.0.last_name", "contractor"),
+					resource.TestCheckResourceAttr(resourceName, "user.0.email", "alice@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "user.0.tags.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "locked_users.#", "0"),
+				),
+			},
+			{
+				Config: testAccCheckLocalUsersBasic(rName, []string{"alice", "dave"}, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLocalUsersExist(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "user.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "user_ids.%", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, fmt.Sprintf("user_ids.%s-dave", rName)),
+					resource.TestCheckNoResourceAttr(resourceName, fmt.Sprintf("user_ids.%s-bob", rName)),
+					resource.TestCheckResourceAttr(resourceName, "unlock_trigger", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLocalUsersBasic(rName string, users []string, unlock string) string {
+	return fmt.Sprintf(`
+locals {
+  contractors = csvdecode(<<-EOT
+    name,first_name,email
+    %s
+  EOT
+  )
+}
+
+resource "appgatesdp_local_users" "contractors" {
+  batch_size     = 2
+  unlock_trigger = "%s"
+
+  dynamic "user" {
+    for_each = local.contractors
+    content {
+      name       = "%s-${user.value.name}"
+      first_name = user.value.first_name
+      last_name  = "contractor"
+      email      = user.value.email
+      password   = "password_is_hunter2"
+      tags       = ["contractor"]
+    }
+  }
+}
+`, testAccLocalUsersCSVRows(users), unlock, rName)
+}
+
+func testAccLocalUsersCSVRows(users []string) string {
+	rows := make([]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, fmt.Sprintf("%s,%s,%s@example.com", u, u, u))
+	}
+	return strings.Join(rows, "\n    ")
+}
+
+func testAccCheckLocalUsersExist(resource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		token, err := testAccProvider.Meta().(*Client).GetToken()
+		if err != nil {
+			return err
+		}
+		api := testAccProvider.Meta().(*Client).API.LocalUsersApi
+
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		for k, id := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "user_ids.") || k == "user_ids.%" {
+				continue
+			}
+			if _, _, err := api.LocalUsersIdGet(BaseAuthContext(token), id).Execute(); err != nil {
+				return fmt.Errorf("error fetching local user %s with resource %s. %s", k, resource, err)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckLocalUsersDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "appgatesdp_local_users" {
+			continue
+		}
+
+		token, err := testAccProvider.Meta().(*Client).GetToken()
+		if err != nil {
+			return err
+		}
+		api := testAccProvider.Meta().(*Client).API.LocalUsersApi
+
+		for k, id := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "user_ids.") || k == "user_ids.%" {
+				continue
+			}
+			if _, _, err := api.LocalUsersIdGet(BaseAuthContext(token), id).Execute(); err == nil {
+				return fmt.Errorf("local user %s still exists", k)
+			}
+		}
+	}
+	return nil
+}
+
+func TestDiffLocalUsers(t *testing.T) {
+	alice := localUserSpec{name: "alice", firstName: "Alice", lastName: "A", tags: []string{}}
+	bob := localUserSpec{name: "bob", firstName: "Bob", lastName: "B", tags: []string{}}
+	carol := localUserSpec{name: "carol", firstName: "Carol", lastName: "C", tags: []string{}}
+	renamed := bob
+	renamed.lastName = "Bee"
+	rotated := alice
+	rotated.passwordVersion = 2
+
+	tests := []struct {
+		name       string
+		old, new   []localUserSpec
+		ids        map[string]string
+		locked     map[string]bool
+		wantCreate []string
+		wantUpdate []string
+		wantRemove []string
+	}{
+		{
+			name:       "create",
+			new:        []localUserSpec{bob, alice},
+			wantCreate: []string{"alice", "bob"},
+			wantUpdate: []string{},
+			wantRemove: []string{},
+		},
+		{
+			name:       "no changes",
+			old:        []localUserSpec{alice, bob},
+			new:        []localUserSpec{alice, bob},
+			ids:        map[string]string{"alice": "1", "bob": "2"},
+			wantCreate: []string{},
+			wantUpdate: []string{},
+			wantRemove: []string{},
+		},
+		{
+			name:       "add, change and remove",
+			old:        []localUserSpec{alice, bob},
+			new:        []localUserSpec{renamed, carol},
+			ids:        map[string]string{"alice": "1", "bob": "2"},
+			wantCreate: []string{"carol"},
+			wantUpdate: []string{"bob"},
+			wantRemove: []string{"alice"},
+		},
+		{
+			name:       "password version and unlock",
+			old:        []localUserSpec{alice, bob},
+			new:        []localUserSpec{rotated, bob},
+			ids:        map[string]string{"alice": "1", "bob": "2"},
+			locked:     map[string]bool{"bob": true},
+			wantCreate: []string{},
+			wantUpdate: []string{"alice", "bob"},
+			wantRemove: []string{},
+		},
+		{
+			name:       "created outside of the list",
+			old:        []localUserSpec{alice},
+			new:        []localUserSpec{alice},
+			ids:        map[string]string{},
+			wantCreate: []string{"alice"},
+			wantUpdate: []string{},
+			wantRemove: []string{},
+		},
+	}
+	toMap := func(specs []localUserSpec) map[string]localUserSpec {
+		m := make(map[string]localUserSpec)
+		for _, s := range specs {
+			m[s.name] = s
+		}
+		return m
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create, update, remove := diffLocalUsers(toMap(tt.old), toMap(tt.new), tt.ids, tt.locked)
+			if !reflect.DeepEqual(create, tt.wantCreate) {
+				t.Errorf("create = %v, want %v", create, tt.wantCreate)
+			}
+			if !reflect.DeepEqual(update, tt.wantUpdate) {
+				t.Errorf("update = %v, want %v", update, tt.wantUpdate)
+			}
+			if !reflect.DeepEqual(remove, tt.wantRemove) {
+				t.Errorf("remove = %v, want %v", remove, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestRemoveLocalUsersFailure(t *testing.T) {
+	ids := map[string]string{"alice": "1", "bob": "2", "carol": "3", "dave": "4", "erin": "5"}
+	err := removeLocalUsers([]string{"alice", "bob", "carol", "dave"}, 2, ids, func(id, name string) error {
+		if name == "bob" {
+			return fmt.Errorf("could not delete local user %s", name)
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "bob") {
+		t.Fatalf("removeLocalUsers() = %v, want the error of bob", err)
+	}
+	// alice is deleted, bob failed and the second batch, carol and dave, is never started.
+	want := map[string]string{"bob": "2", "carol": "3", "dave": "4", "erin": "5"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+
+	// Update sets user_ids before it returns the error, so the users that still exist stay in the state.
+	d := schema.TestResourceDataRaw(t, resourceAppgateLocalUsers().Schema, map[string]interface{}{})
+	setLocalUserIDs(d, ids)
+	got := d.Get("user_ids").(map[string]interface{})
+	if len(got) != len(want) {
+		t.Fatalf("user_ids = %v, want %v", got, want)
+	}
+	for name, id := range want {
+		if got[name] != id {
+			t.Errorf("user_ids[%s] = %v, want %s", name, got[name], id)
+		}
+	}
+}
+
+func TestInBatches(t *testing.T) {
+	var calls int32
+	err := inBatches(7, 3, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	if err != nil || calls != 7 {
+		t.Fatalf("inBatches() = %v with %d calls, want 7 calls", err, calls)
+	}
+
+	calls = 0
+	err = inBatches(7, 3, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 4 {
+			return fmt.Errorf("user %d failed", i)
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "user 4 failed") {
+		t.Fatalf("inBatches() = %v, want the error of user 4", err)
+	}
+	// the third batch is never started.
+	if calls != 6 {
+		t.Fatalf("inBatches() called fn %d times, want 6", calls)
+	}
+}
@@ -18,7 +18,7 @@ func resourceAppgateMfaProvider() *schema.Resource {
 		Update: resourceAppgateMfaProviderUpdate,
 		Delete: resourceAppgateMfaProviderDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importPreventDestroyIfReferenced,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -27,8 +27,12 @@ func resourceAppgateMfaProvider() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			preferWriteOnlySecret("shared_secret"),
+		},
+
 		SchemaVersion: 1,
-		Schema: map[string]*schema.Schema{
+		Schema: mergeSchemaMaps(writeOnlySecretSchema("shared_secret", "shared_secret", "Shared secret to authenticate to the RADIUS server of the MFA provider."), map[string]*schema.Schema{
 
 			"mfa_provider_id": resourceUUID(),
 			"name": {
@@ -46,6 +50,10 @@ func resourceAppgateMfaProvider() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
+			"prevent_destroy_if_referenced": preventDestroyIfReferencedSchema(),
+
 			"type": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -137,7 +145,7 @@ func resourceAppgateMfaProvider() *schema.Resource {
 				Sensitive: true,
 				Computed:  true,
 			},
-		},
+		}),
 	}
 }
 
@@ -168,8 +176,8 @@ func resourceAppgateMfaProviderCreate(d *schema.ResourceData, meta interface{})
 	if v, ok := d.GetOk("port"); ok {
 		args.SetPort(float32(v.(int)))
 	}
-	if v, ok := d.GetOk("shared_secret"); ok {
-		args.SetSharedSecret(v.(string))
+	if v, ok := secretFromConfig(d, "shared_secret"); ok {
+		args.SetSharedSecret(v)
 	}
 	if v, ok := d.GetOk("input_type"); ok {
 		args.SetInputType(v.(string))
@@ -221,6 +229,7 @@ func resourceAppgateMfaProviderRead(d *schema.ResourceData, meta interface{}) er
 		}
 		return fmt.Errorf("Failed to read MFA provider, %w", err)
 	}
+	setUpdated(d, mfaProvider.GetUpdated())
 	d.SetId(mfaProvider.GetId())
 	d.Set("mfa_provider_id", mfaProvider.GetId())
 	d.Set("name", mfaProvider.GetName())
@@ -251,6 +260,9 @@ func resourceAppgateMfaProviderUpdate(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return fmt.Errorf("Failed to read MFA provider while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateMfaProvider(), originalMfaProvider.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalMfaProvider.SetName(d.Get("name").(string))
@@ -276,8 +288,9 @@ func resourceAppgateMfaProviderUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("port") {
 		originalMfaProvider.SetPort(float32(d.Get("port").(int)))
 	}
-	if d.HasChange("shared_secret") {
-		originalMfaProvider.SetSharedSecret(d.Get("shared_secret").(string))
+	if secretHasChange(d, "shared_secret") {
+		v, _ := secretFromConfig(d, "shared_secret")
+		originalMfaProvider.SetSharedSecret(v)
 	}
 	if d.HasChange("input_type") {
 		originalMfaProvider.SetInputType(d.Get("input_type").(string))
@@ -312,6 +325,9 @@ func resourceAppgateMfaProviderDelete(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return err
 	}
+	if err := checkReferencesBeforeDelete(BaseAuthContext(token), d, meta, "MFA provider", referenceQuery{ID: d.Id()}); err != nil {
+		return err
+	}
 	api := meta.(*Client).API.MFAProvidersApi
 	if _, err := api.MfaProvidersIdDelete(BaseAuthContext(token), d.Id()).Execute(); err != nil {
 		return fmt.Errorf("Could not delete MFA provider %w", prettyPrintAPIError(err))
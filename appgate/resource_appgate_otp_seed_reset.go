@@ -0,0 +1,74 @@
+package appgate
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// userDistinguishedNameRegex matches the distinguished name of a user, OTP seeds are per user and not per device.
+var userDistinguishedNameRegex = regexp.MustCompile(`(?i)^CN=[^,]+,OU=[^,]+$`)
+
+// resourceAppgateOtpSeedReset removes the OTP seed of a user when it is created.
+// The user registers a new authenticator on the next sign in.
+func resourceAppgateOtpSeedReset() *schema.Resource {
+	return oneShotActionResource(&schema.Resource{
+		CreateContext: resourceAppgateOtpSeedResetCreate,
+
+		Schema: map[string]*schema.Schema{
+			"distinguished_name": {
+				Type:         schema.TypeString,
+				Description:  "Distinguished name of the user, CN=<username>,OU=<provider name>.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(userDistinguishedNameRegex, "must be CN=<username>,OU=<provider name>"),
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Description: "Reason for the reset, such as a helpdesk ticket, kept in the state.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"reset_at": {
+				Type:        schema.TypeString,
+				Description: "Time of the reset.",
+				Computed:    true,
+			},
+			"seed_removed": {
+				Type:        schema.TypeBool,
+				Description: "Whether the user had an OTP seed that was removed.",
+				Computed:    true,
+			},
+		},
+	}, "reset the seed again")
+}
+
+func resourceAppgateOtpSeedResetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dn := d.Get("distinguished_name").(string)
+	log.Printf("[INFO] Resetting OTP seed of %s: %s", dn, d.Get("reason"))
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.OtpApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	removed := true
+	response, err := api.OtpSeedsDistinguishedNameDelete(ctx, dn).Execute()
+	if err != nil {
+		if response == nil || response.StatusCode != http.StatusNotFound {
+			return diag.Errorf("Could not reset the OTP seed of %s, %s", dn, prettyPrintAPIError(err))
+		}
+		// the user never registered an authenticator, there is nothing to reset.
+		removed = false
+	}
+	setOneShotActionID(d, "reset_at")
+	d.Set("seed_removed", removed)
+	return nil
+}
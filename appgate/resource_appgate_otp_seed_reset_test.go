@@ -0,0 +1,42 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOtpSeedReset(t *testing.T) {
+	resourceName := "appgatesdp_otp_seed_reset.lost_phone"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOtpSeedResetConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "distinguished_name", fmt.Sprintf("CN=%s,OU=local", rName)),
+					resource.TestCheckResourceAttrSet(resourceName, "reset_at"),
+					// the user has never registered an authenticator.
+					resource.TestCheckResourceAttr(resourceName, "seed_removed", "false"),
+					resource.TestCheckResourceAttr("data.appgatesdp_otp_seeds.user", "otp_seeds.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOtpSeedResetConfig(rName string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_otp_seeds" "user" {
+  distinguished_name = "CN=%[1]s,OU=local"
+}
+
+resource "appgatesdp_otp_seed_reset" "lost_phone" {
+  distinguished_name = "CN=%[1]s,OU=local"
+  reason             = "HELP-42"
+}
+`, rName)
+}
@@ -56,6 +56,71 @@ func basePolicySchema() map[string]*schema.Schema {
 	})
 }
 
+// policyReferencesAuthoritativeSchema is added with the references that the policy attachment resources manage.
+func policyReferencesAuthoritativeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     true,
+		Description: "If false, entitlements, entitlement_links, ringfence_rules, ringfence_rule_links and administrative_roles not declared in this resource, for example managed by the policy attachment resources, are left untouched.",
+	}
+}
+
+func resourceAppgatePolicyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// imported policies owns all references until configured otherwise.
+	d.Set("references_authoritative", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+// managedPolicyReferences returns the references in remote, or if references_authoritative is false,
+// only the references in remote that are declared in the resource, to ignore references added
+// by the policy attachment resources or by administrators.
+func managedPolicyReferences(d *schema.ResourceData, attribute string, remote []string) []string {
+	managed, ok := d.Get(attribute).(*schema.Set)
+	if d.Get("references_authoritative").(bool) || !ok {
+		return remote
+	}
+	declared, _ := readArrayOfStringsFromConfig(managed.List())
+	result := make([]string, 0, len(remote))
+	for _, v := range remote {
+		if containsFold(declared, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// changedPolicyReferences returns the declared references of attribute, merged with
+// the references in remote if references_authoritative is false.
+func changedPolicyReferences(d *schema.ResourceData, attribute string, remote []string) ([]string, error) {
+	o, n := d.GetChange(attribute)
+	declared, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+	if err != nil {
+		return nil, err
+	}
+	if d.Get("references_authoritative").(bool) {
+		return declared, nil
+	}
+	old, err := readArrayOfStringsFromConfig(o.(*schema.Set).List())
+	if err != nil {
+		return nil, err
+	}
+	return mergePolicyReferences(remote, old, declared), nil
+}
+
+// mergePolicyReferences replaces the previously declared references in remote with
+// the declared references, and leaves everything else untouched.
+func mergePolicyReferences(remote, old, new []string) []string {
+	result := make([]string, 0, len(remote)+len(new))
+	for _, v := range remote {
+		if containsFold(old, v) || containsFold(new, v) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return append(result, new...)
+}
+
 func basePolicyEntitlementAttributes() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"entitlements": {
@@ -71,6 +136,8 @@ func basePolicyEntitlementAttributes() map[string]*schema.Schema {
 			Set:      schema.HashString,
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
+
+		"references_authoritative": policyReferencesAuthoritativeSchema(),
 	}
 }
 func basePolicyRingfenceAttributes() map[string]*schema.Schema {
@@ -88,6 +155,8 @@ func basePolicyRingfenceAttributes() map[string]*schema.Schema {
 			Set:      schema.HashString,
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
+
+		"references_authoritative": policyReferencesAuthoritativeSchema(),
 	}
 }
 func basePolicyAdminAttributes() map[string]*schema.Schema {
@@ -98,6 +167,8 @@ func basePolicyAdminAttributes() map[string]*schema.Schema {
 			Set:      schema.HashString,
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
+
+		"references_authoritative": policyReferencesAuthoritativeSchema(),
 	}
 }
 func basePolicyDeviceAttributes() map[string]*schema.Schema {
@@ -317,7 +388,7 @@ func resourceAppgatePolicy() *schema.Resource {
 		UpdateContext: resourceAppgatePolicyUpdate,
 		DeleteContext: resourceAppgatePolicyDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAppgatePolicyImport,
 		},
 
 		SchemaVersion: 1,
@@ -677,6 +748,7 @@ func resourceAppgatePolicyRead(ctx context.Context, d *schema.ResourceData, meta
 		}
 		return diag.Errorf("Failed to read policy, %s", err)
 	}
+	setUpdated(d, policy.GetUpdated())
 	d.Set("policy_id", policy.GetId())
 	d.Set("name", policy.GetName())
 	d.Set("notes", policy.GetNotes())
@@ -685,22 +757,22 @@ func resourceAppgatePolicyRead(ctx context.Context, d *schema.ResourceData, meta
 	d.Set("tags", policy.GetTags())
 
 	if v := d.Get("entitlements"); v != nil {
-		d.Set("entitlements", policy.GetEntitlements())
+		d.Set("entitlements", managedPolicyReferences(d, "entitlements", policy.GetEntitlements()))
 	}
 	if v := d.Get("entitlement_links"); v != nil {
-		d.Set("entitlement_links", policy.GetEntitlementLinks())
+		d.Set("entitlement_links", managedPolicyReferences(d, "entitlement_links", policy.GetEntitlementLinks()))
 	}
 	if v := d.Get("ringfence_rule_links"); v != nil {
-		d.Set("ringfence_rule_links", policy.GetRingfenceRuleLinks())
+		d.Set("ringfence_rule_links", managedPolicyReferences(d, "ringfence_rule_links", policy.GetRingfenceRuleLinks()))
 	}
 	if v := d.Get("ringfence_rules"); v != nil {
-		d.Set("ringfence_rules", policy.GetRingfenceRules())
+		d.Set("ringfence_rules", managedPolicyReferences(d, "ringfence_rules", policy.GetRingfenceRules()))
 	}
 	if v := d.Get("tamper_proofing"); v != nil {
 		d.Set("tamper_proofing", policy.GetTamperProofing())
 	}
 	if v := d.Get("administrative_roles"); v != nil {
-		d.Set("administrative_roles", policy.GetAdministrativeRoles())
+		d.Set("administrative_roles", managedPolicyReferences(d, "administrative_roles", policy.GetAdministrativeRoles()))
 	}
 
 	if v, o := policy.GetProxyAutoConfigOk(); o != false {
@@ -864,12 +936,18 @@ func resourceAppgatePolicyUpdate(ctx context.Context, d *schema.ResourceData, me
 	}
 	api := meta.(*Client).API.PoliciesApi
 	currentVersion := meta.(*Client).ApplianceVersion
+	// policy attachment resources may update the same policy concurrently.
+	resourceLocks.Lock(d.Id())
+	defer resourceLocks.Unlock(d.Id())
 	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
 	request := api.PoliciesIdGet(ctx, d.Id())
 	orginalPolicy, _, err := request.Execute()
 	if err != nil {
 		return diag.Errorf("Failed to read policy, %s", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgatePolicy(), orginalPolicy.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		orginalPolicy.SetName(d.Get("name").(string))
@@ -892,39 +970,35 @@ func resourceAppgatePolicyUpdate(ctx context.Context, d *schema.ResourceData, me
 	}
 
 	if d.HasChange("entitlements") {
-		_, n := d.GetChange("entitlements")
-		entitlements, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+		references, err := changedPolicyReferences(d, "entitlements", orginalPolicy.GetEntitlements())
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		orginalPolicy.SetEntitlements(entitlements)
+		orginalPolicy.SetEntitlements(references)
 	}
 
 	if d.HasChange("entitlement_links") {
-		_, n := d.GetChange("entitlement_links")
-		entitlements, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+		references, err := changedPolicyReferences(d, "entitlement_links", orginalPolicy.GetEntitlementLinks())
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		orginalPolicy.SetEntitlementLinks(entitlements)
+		orginalPolicy.SetEntitlementLinks(references)
 	}
 
 	if d.HasChange("ringfence_rules") {
-		_, n := d.GetChange("ringfence_rules")
-		entitlements, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+		references, err := changedPolicyReferences(d, "ringfence_rules", orginalPolicy.GetRingfenceRules())
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		orginalPolicy.SetRingfenceRules(entitlements)
+		orginalPolicy.SetRingfenceRules(references)
 	}
 
 	if d.HasChange("ringfence_rule_links") {
-		_, n := d.GetChange("ringfence_rule_links")
-		entitlements, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+		references, err := changedPolicyReferences(d, "ringfence_rule_links", orginalPolicy.GetRingfenceRuleLinks())
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		orginalPolicy.SetRingfenceRuleLinks(entitlements)
+		orginalPolicy.SetRingfenceRuleLinks(references)
 	}
 
 	if d.HasChange("tamper_proofing") {
@@ -942,12 +1016,11 @@ func resourceAppgatePolicyUpdate(ctx context.Context, d *schema.ResourceData, me
 	}
 
 	if d.HasChange("administrative_roles") {
-		_, n := d.GetChange("administrative_roles")
-		entitlements, err := readArrayOfStringsFromConfig(n.(*schema.Set).List())
+		references, err := changedPolicyReferences(d, "administrative_roles", orginalPolicy.GetAdministrativeRoles())
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		orginalPolicy.SetAdministrativeRoles(entitlements)
+		orginalPolicy.SetAdministrativeRoles(references)
 	}
 	if d.HasChange("client_settings") {
 		_, v := d.GetChange("client_settings")
@@ -0,0 +1,313 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// policyReference is a list of references on a policy, such as entitlements or entitlement_links,
+// that can be managed one item at a time by a policy attachment resource.
+type policyReference struct {
+	// attribute is the name of the attribute in the attachment resource.
+	attribute   string
+	description string
+	isUUID      bool
+	get         func(p *openapi.Policy) []string
+	set         func(p *openapi.Policy, v []string)
+}
+
+func policyEntitlementReferences() []policyReference {
+	return []policyReference{
+		{
+			attribute:   "entitlement_id",
+			description: "ID of the Entitlement to add to the Policy entitlements.",
+			isUUID:      true,
+			get:         func(p *openapi.Policy) []string { return p.GetEntitlements() },
+			set:         func(p *openapi.Policy, v []string) { p.SetEntitlements(v) },
+		},
+		{
+			attribute:   "entitlement_link",
+			description: "Entitlement tag to add to the Policy entitlement_links.",
+			get:         func(p *openapi.Policy) []string { return p.GetEntitlementLinks() },
+			set:         func(p *openapi.Policy, v []string) { p.SetEntitlementLinks(v) },
+		},
+	}
+}
+
+func policyRingfenceRuleReferences() []policyReference {
+	return []policyReference{
+		{
+			attribute:   "ringfence_rule_id",
+			description: "ID of the Ringfence Rule to add to the Policy ringfence_rules.",
+			isUUID:      true,
+			get:         func(p *openapi.Policy) []string { return p.GetRingfenceRules() },
+			set:         func(p *openapi.Policy, v []string) { p.SetRingfenceRules(v) },
+		},
+		{
+			attribute:   "ringfence_rule_link",
+			description: "Ringfence Rule tag to add to the Policy ringfence_rule_links.",
+			get:         func(p *openapi.Policy) []string { return p.GetRingfenceRuleLinks() },
+			set:         func(p *openapi.Policy, v []string) { p.SetRingfenceRuleLinks(v) },
+		},
+	}
+}
+
+func policyAdminRoleReferences() []policyReference {
+	return []policyReference{
+		{
+			attribute:   "administrative_role_id",
+			description: "ID of the Administrative Role to add to the Policy administrative_roles.",
+			isUUID:      true,
+			get:         func(p *openapi.Policy) []string { return p.GetAdministrativeRoles() },
+			set:         func(p *openapi.Policy, v []string) { p.SetAdministrativeRoles(v) },
+		},
+	}
+}
+
+func resourceAppgatePolicyEntitlementAttachment() *schema.Resource {
+	return resourceAppgatePolicyAttachment(policyEntitlementReferences())
+}
+
+func resourceAppgatePolicyRingfenceRuleAttachment() *schema.Resource {
+	return resourceAppgatePolicyAttachment(policyRingfenceRuleReferences())
+}
+
+func resourceAppgatePolicyAdminRoleAttachment() *schema.Resource {
+	return resourceAppgatePolicyAttachment(policyAdminRoleReferences())
+}
+
+// resourceAppgatePolicyAttachment adds a single reference to an existing policy,
+// without managing the rest of the policy, similar to IAM policy attachments.
+func resourceAppgatePolicyAttachment(references []policyReference) *schema.Resource {
+	attributes := make([]string, 0, len(references))
+	for _, ref := range references {
+		attributes = append(attributes, ref.attribute)
+	}
+	s := map[string]*schema.Schema{
+		"policy_id": {
+			Type:         schema.TypeString,
+			Description:  "ID of the Policy.",
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IsUUID,
+		},
+	}
+	for _, ref := range references {
+		attr := &schema.Schema{
+			Type:         schema.TypeString,
+			Description:  ref.description,
+			Optional:     len(references) > 1,
+			Required:     len(references) == 1,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+		}
+		if ref.isUUID {
+			attr.ValidateFunc = validation.IsUUID
+		}
+		if len(references) > 1 {
+			attr.ExactlyOneOf = attributes
+		}
+		s[ref.attribute] = attr
+	}
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgatePolicyAttachmentCreate(ctx, d, meta, references)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgatePolicyAttachmentRead(ctx, d, meta, references)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceAppgatePolicyAttachmentDelete(ctx, d, meta, references)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				return resourceAppgatePolicyAttachmentImport(ctx, d, meta, references)
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: s,
+	}
+}
+
+// configuredPolicyReference returns the reference and value that is set in the attachment.
+func configuredPolicyReference(d *schema.ResourceData, references []policyReference) (policyReference, string, error) {
+	attributes := make([]string, 0, len(references))
+	for _, ref := range references {
+		if v, ok := d.GetOk(ref.attribute); ok {
+			return ref, v.(string), nil
+		}
+		attributes = append(attributes, ref.attribute)
+	}
+	return policyReference{}, "", fmt.Errorf("one of %v must be set", attributes)
+}
+
+// updatePolicy does a read-modify-write of a policy, see readModifyWrite.
+func updatePolicy(ctx context.Context, meta interface{}, policyID string, timeout time.Duration, modify func(p *openapi.Policy) (bool, error), applied func(p *openapi.Policy) bool) error {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return err
+	}
+	api := meta.(*Client).API.PoliciesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	read := func() (*openapi.Policy, error) {
+		policy, response, err := api.PoliciesIdGet(ctx, policyID).Execute()
+		if err != nil {
+			if response != nil && response.StatusCode == http.StatusNotFound {
+				return nil, fmt.Errorf("Policy %s does not exist", policyID)
+			}
+			return nil, fmt.Errorf("Failed to read Policy %s, %w", policyID, prettyPrintAPIError(err))
+		}
+		return policy, nil
+	}
+	write := func(policy *openapi.Policy) error {
+		if _, _, err := api.PoliciesIdPut(ctx, policyID).Policy(*policy).Execute(); err != nil {
+			return fmt.Errorf("Could not update Policy %s %w", policyID, prettyPrintAPIError(err))
+		}
+		return nil
+	}
+	return readModifyWrite(ctx, policyID, timeout, read, func(policy *openapi.Policy) (*openapi.Policy, bool, error) {
+		changed, err := modify(policy)
+		return policy, changed, err
+	}, write, applied)
+}
+
+// containsFold returns true if list contains value, ignoring case.
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceAppgatePolicyAttachmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}, references []policyReference) diag.Diagnostics {
+	policyID := d.Get("policy_id").(string)
+	ref, value, err := configuredPolicyReference(d, references)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[DEBUG] Attaching %s %s to Policy %s", ref.attribute, value, policyID)
+	err = updatePolicy(ctx, meta, policyID, d.Timeout(schema.TimeoutCreate), func(p *openapi.Policy) (bool, error) {
+		existing := ref.get(p)
+		if containsFold(existing, value) {
+			return false, fmt.Errorf("%s %s is already attached to Policy %s, import it with terraform import", ref.attribute, value, policyID)
+		}
+		ref.set(p, append(existing, value))
+		return true, nil
+	}, func(p *openapi.Policy) bool {
+		return containsFold(ref.get(p), value)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not attach %s to Policy %w", ref.attribute, err))
+	}
+	d.SetId(fmt.Sprintf("%s/%s", policyID, value))
+	return resourceAppgatePolicyAttachmentRead(ctx, d, meta, references)
+}
+
+func resourceAppgatePolicyAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}, references []policyReference) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading Policy attachment id: %+v", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.PoliciesApi
+	policyID := d.Get("policy_id").(string)
+	ref, value, err := configuredPolicyReference(d, references)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+	policy, response, err := api.PoliciesIdGet(ctx, policyID).Execute()
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Failed to read policy, %s", err)
+	}
+	for _, v := range ref.get(policy) {
+		if strings.EqualFold(v, value) {
+			d.Set(ref.attribute, v)
+			return nil
+		}
+	}
+	log.Printf("[WARN] %s %s is no longer attached to Policy %s", ref.attribute, value, policyID)
+	d.SetId("")
+	return nil
+}
+
+func resourceAppgatePolicyAttachmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, references []policyReference) diag.Diagnostics {
+	policyID := d.Get("policy_id").(string)
+	ref, value, err := configuredPolicyReference(d, references)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[DEBUG] Detaching %s %s from Policy %s", ref.attribute, value, policyID)
+	err = updatePolicy(ctx, meta, policyID, d.Timeout(schema.TimeoutDelete), func(p *openapi.Policy) (bool, error) {
+		existing := ref.get(p)
+		if !containsFold(existing, value) {
+			return false, nil
+		}
+		result := make([]string, 0, len(existing))
+		for _, v := range existing {
+			if !strings.EqualFold(v, value) {
+				result = append(result, v)
+			}
+		}
+		ref.set(p, result)
+		return true, nil
+	}, func(p *openapi.Policy) bool {
+		return !containsFold(ref.get(p), value)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not detach %s from Policy %w", ref.attribute, err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceAppgatePolicyAttachmentImport accepts <policy_id>/<value>, where value is
+// an ID or a tag that is already referenced by the policy.
+func resourceAppgatePolicyAttachmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}, references []policyReference) ([]*schema.ResourceData, error) {
+	policyID, value, found := strings.Cut(d.Id(), "/")
+	if !found || len(value) == 0 {
+		return nil, fmt.Errorf("invalid import id %q, expected <policy_id>/<value>", d.Id())
+	}
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API.PoliciesApi
+	policy, _, err := api.PoliciesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), policyID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read Policy %s, %w", policyID, prettyPrintAPIError(err))
+	}
+	for _, ref := range references {
+		for _, v := range ref.get(policy) {
+			if strings.EqualFold(v, value) {
+				d.Set("policy_id", policyID)
+				d.Set(ref.attribute, v)
+				d.SetId(fmt.Sprintf("%s/%s", policyID, v))
+				return []*schema.ResourceData{d}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s is not referenced by Policy %s", value, policyID)
+}
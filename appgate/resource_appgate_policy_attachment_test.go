@@ -0,0 +1,107 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPolicyEntitlementAttachment(t *testing.T) {
+	policyName := "appgatesdp_access_policy.shared"
+	resourceName := "appgatesdp_policy_entitlement_attachment.team_a"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyEntitlementAttachment(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyExists(policyName),
+					resource.TestCheckResourceAttrPair(resourceName, "policy_id", policyName, "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "entitlement_id", "appgatesdp_entitlement.team_a", "id"),
+					resource.TestCheckResourceAttr("appgatesdp_policy_entitlement_attachment.developers", "entitlement_link", "developer"),
+					testAccCheckPolicyReferences(policyName, 1, 1),
+					resource.TestCheckResourceAttr(policyName, "references_authoritative", "false"),
+					resource.TestCheckResourceAttr(policyName, "entitlements.#", "0"),
+					resource.TestCheckResourceAttr(policyName, "entitlement_links.#", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyReferences(resource string, entitlements, entitlementLinks int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		token, err := testAccProvider.Meta().(*Client).GetToken()
+		if err != nil {
+			return err
+		}
+		api := testAccProvider.Meta().(*Client).API.PoliciesApi
+
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resource)
+		}
+		policy, _, err := api.PoliciesIdGet(BaseAuthContext(token), rs.Primary.ID).Execute()
+		if err != nil {
+			return fmt.Errorf("error fetching item with resource %s. %s", resource, err)
+		}
+		if got := len(policy.GetEntitlements()); got != entitlements {
+			return fmt.Errorf("expected %d entitlements on %s, got %d", entitlements, resource, got)
+		}
+		if got := len(policy.GetEntitlementLinks()); got != entitlementLinks {
+			return fmt.Errorf("expected %d entitlement_links on %s, got %d", entitlementLinks, resource, got)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicyEntitlementAttachment(rName string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_site" "default_site" {
+  site_name = "Default Site"
+}
+data "appgatesdp_condition" "always" {
+  condition_name = "Always"
+}
+resource "appgatesdp_access_policy" "shared" {
+  name       = "%[1]s"
+  expression = "return true;"
+
+  references_authoritative = false
+}
+
+resource "appgatesdp_entitlement" "team_a" {
+  name = "%[1]s"
+  site = data.appgatesdp_site.default_site.id
+  conditions = [
+    data.appgatesdp_condition.always.id
+  ]
+  actions {
+    action  = "allow"
+    subtype = "tcp_up"
+    hosts   = ["team-a.company.com"]
+    ports   = ["443"]
+  }
+}
+
+resource "appgatesdp_policy_entitlement_attachment" "team_a" {
+  policy_id      = appgatesdp_access_policy.shared.id
+  entitlement_id = appgatesdp_entitlement.team_a.id
+}
+
+resource "appgatesdp_policy_entitlement_attachment" "developers" {
+  policy_id        = appgatesdp_access_policy.shared.id
+  entitlement_link = "developer"
+}
+`, rName)
+}
@@ -2,6 +2,7 @@ package appgate
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -930,3 +931,12 @@ resource "appgatesdp_policy" "test_policy62" {
 }
 `, context)
 }
+
+func TestMergePolicyReferences(t *testing.T) {
+	remote := []string{"attached-by-team-a", "Declared", "removed"}
+	got := mergePolicyReferences(remote, []string{"declared", "removed"}, []string{"declared", "added"})
+	want := []string{"attached-by-team-a", "declared", "added"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergePolicyReferences() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceAppgateRegisteredDeviceRemoval removes the registered devices of a user, or a single device, when it is created.
+// The user has to register the device again on the next sign in.
+func resourceAppgateRegisteredDeviceRemoval() *schema.Resource {
+	return oneShotActionResource(&schema.Resource{
+		CreateContext: resourceAppgateRegisteredDeviceRemovalCreate,
+
+		Schema: map[string]*schema.Schema{
+			"distinguished_name": {
+				Type:         schema.TypeString,
+				Description:  "Distinguished name of the user, CN=<username>,OU=<provider name>, to remove all devices of the user, or of the device, CN=<device ID>,CN=<username>,OU=<provider name>.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(distinguishedNameRegex, "must be CN=<username>,OU=<provider name> or CN=<device ID>,CN=<username>,OU=<provider name>"),
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Description: "Reason for the removal, such as a helpdesk ticket, kept in the state.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"removed_at": {
+				Type:        schema.TypeString,
+				Description: "Time of the removal.",
+				Computed:    true,
+			},
+			"removed_devices": {
+				Type:        schema.TypeList,
+				Description: "Devices removed.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"device_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}, "remove the devices again")
+}
+
+func resourceAppgateRegisteredDeviceRemovalCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dn := d.Get("distinguished_name").(string)
+	devices, err := listRegisteredDevices(ctx, meta, distinguishedNameFilter{distinguishedName: dn})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.RegisteredDevicesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	// the removed devices are recorded before a failed removal is returned, so they are kept in the state.
+	setOneShotActionID(d, "removed_at")
+	removed := make([]interface{}, 0, len(devices))
+	var diags diag.Diagnostics
+	for _, device := range devices {
+		log.Printf("[INFO] Removing registered device %s: %s", device.GetDistinguishedName(), d.Get("reason"))
+		response, err := api.RegisteredDevicesDistinguishedNameDelete(ctx, device.GetDistinguishedName()).Execute()
+		if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+			diags = diag.Errorf("Could not remove registered device %s, %s", device.GetDistinguishedName(), prettyPrintAPIError(err))
+			break
+		}
+		removed = append(removed, map[string]interface{}{
+			"distinguished_name": device.GetDistinguishedName(),
+			"device_id":          device.GetDeviceId(),
+			"hostname":           device.GetHostname(),
+		})
+	}
+	if err := d.Set("removed_devices", removed); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if len(devices) == 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "No registered devices removed",
+			Detail:   fmt.Sprintf("%s does not match any registered device, check the username, device ID and identity provider name.", dn),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,43 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRegisteredDeviceRemoval(t *testing.T) {
+	resourceName := "appgatesdp_registered_device_removal.lost_phone"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegisteredDeviceRemovalConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "distinguished_name", fmt.Sprintf("CN=%s,OU=local", rName)),
+					resource.TestCheckResourceAttr(resourceName, "reason", "HELP-42"),
+					resource.TestCheckResourceAttrSet(resourceName, "removed_at"),
+					// the user has never signed in, so there are no devices to remove.
+					resource.TestCheckResourceAttr(resourceName, "removed_devices.#", "0"),
+					resource.TestCheckResourceAttr("data.appgatesdp_registered_devices.user", "registered_devices.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRegisteredDeviceRemovalConfig(rName string) string {
+	return fmt.Sprintf(`
+data "appgatesdp_registered_devices" "user" {
+  distinguished_name = "CN=%[1]s,OU=local"
+}
+
+resource "appgatesdp_registered_device_removal" "lost_phone" {
+  distinguished_name = "CN=%[1]s,OU=local"
+  reason             = "HELP-42"
+}
+`, rName)
+}
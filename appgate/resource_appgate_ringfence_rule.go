@@ -47,6 +47,8 @@ func resourceAppgateRingfenceRule() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"actions": {
 				Type:     schema.TypeList,
 				Required: true,
@@ -179,6 +181,7 @@ func resourceAppgateRingfenceRuleRead(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return fmt.Errorf("Failed to read Ringfence rule, %w", err)
 	}
+	setUpdated(d, ringfenceRule.GetUpdated())
 	d.Set("ringfence_rule_id", ringfenceRule.GetId())
 	d.Set("name", ringfenceRule.Name)
 	d.Set("notes", ringfenceRule.Notes)
@@ -231,6 +234,9 @@ func resourceAppgateRingfenceRuleUpdate(d *schema.ResourceData, meta interface{}
 	if err != nil {
 		return fmt.Errorf("Failed to read Ringfence rule, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateRingfenceRule(), originalRingfenceRule.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalRingfenceRule.SetName(d.Get("name").(string))
@@ -54,6 +54,8 @@ func resourceAppgateSite() *schema.Resource {
 
 			"tags": tagsSchema(),
 
+			"updated": updatedSchema(),
+
 			"short_name": {
 				Type:        schema.TypeString,
 				Description: "A short 4 letter name for the site",
@@ -637,6 +639,7 @@ func resourceAppgateSiteRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		return fmt.Errorf("Failed to read Site, %w", err)
 	}
+	setUpdated(d, site.GetUpdated())
 
 	d.SetId(site.GetId())
 	d.Set("site_id", site.GetId())
@@ -974,6 +977,9 @@ func resourceAppgateSiteUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 		return fmt.Errorf("Failed to read Site, %w", err)
 	}
+	if err := checkConcurrentUpdate(BaseAuthContext(token), d, meta, resourceAppgateSite(), orginalSite.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		orginalSite.SetName(d.Get("name").(string))
@@ -0,0 +1,284 @@
+package appgate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	applianceFunctionGateway      = "gateway"
+	applianceFunctionLogServer    = "log_server"
+	applianceFunctionLogForwarder = "log_forwarder"
+	applianceFunctionConnector    = "connector"
+	applianceFunctionPortal       = "portal"
+	applianceFunctionController   = "controller"
+)
+
+func siteUpgradeFunctions() []string {
+	return []string{
+		applianceFunctionGateway,
+		applianceFunctionLogServer,
+		applianceFunctionLogForwarder,
+		applianceFunctionConnector,
+		applianceFunctionPortal,
+	}
+}
+
+func resourceAppgateSiteUpgrade() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppgateSiteUpgradeCreate,
+		ReadContext:   resourceAppgateSiteUpgradeRead,
+		DeleteContext: resourceAppgateSiteUpgradeDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Hour),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"site_id": {
+				Type:         schema.TypeString,
+				Description:  "ID of the site to upgrade.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"image_url": {
+				Type:         schema.TypeString,
+				Description:  "URL of the upgrade image, reachable from the appliances.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "controller"}),
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Description: "Version of the upgrade image. Appliances that already run this version are skipped.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"functions": {
+				Type:        schema.TypeSet,
+				Description: "Upgrade the appliances in the site with any of these functions enabled, defaults to gateway and log_server. Controllers are never upgraded by this resource.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(siteUpgradeFunctions(), false),
+				},
+			},
+			"exclude": {
+				Type:        schema.TypeSet,
+				Description: "IDs of appliances in the site to leave out of the upgrade.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsUUID,
+				},
+			},
+			"batch_size": {
+				Type:         schema.TypeInt,
+				Description:  "Number of appliances upgraded at the same time.",
+				Optional:     true,
+				Default:      1,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"switch_partition": {
+				Type:        schema.TypeBool,
+				Description: "Reboot into the new version when the upgrade completes. If false, the new version is used after the next reboot.",
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"upgraded": {
+				Type:        schema.TypeList,
+				Description: "IDs of the appliances upgraded, in order.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"skipped": {
+				Type:        schema.TypeList,
+				Description: "IDs of the appliances that already ran version.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "Status of the upgrade.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// siteUpgradeFunctionsOrDefault returns functions, or gateway and log_server if functions is empty.
+// The default is applied here since the SDK does not apply defaults to sets.
+func siteUpgradeFunctionsOrDefault(functions []string) []string {
+	if len(functions) == 0 {
+		return []string{applianceFunctionGateway, applianceFunctionLogServer}
+	}
+	return functions
+}
+
+// applianceHasFunction returns true if any of functions is enabled on the appliance.
+func applianceHasFunction(appliance openapi.Appliance, functions []string) bool {
+	for _, f := range functions {
+		var enabled bool
+		switch f {
+		case applianceFunctionGateway:
+			v := appliance.GetGateway()
+			enabled = v.GetEnabled()
+		case applianceFunctionLogServer:
+			v := appliance.GetLogServer()
+			enabled = v.GetEnabled()
+		case applianceFunctionLogForwarder:
+			v := appliance.GetLogForwarder()
+			enabled = v.GetEnabled()
+		case applianceFunctionConnector:
+			v := appliance.GetConnector()
+			enabled = v.GetEnabled()
+		case applianceFunctionPortal:
+			v := appliance.GetPortal()
+			enabled = v.GetEnabled()
+		}
+		if enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// siteUpgradeAppliances returns the activated appliances in the site with any of functions, sorted by name.
+func siteUpgradeAppliances(ctx context.Context, meta interface{}, siteID string, functions, exclude []string) ([]openapi.Appliance, error) {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return nil, err
+	}
+	api := meta.(*Client).API.AppliancesApi
+	list, _, err := api.AppliancesGet(context.WithValue(ctx, openapi.ContextAccessToken, token)).OrderBy("name").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list appliances, %w", prettyPrintAPIError(err))
+	}
+	appliances := make([]openapi.Appliance, 0)
+	for _, a := range list.GetData() {
+		ctrl := a.GetController()
+		if a.GetSite() != siteID || !a.GetActivated() || ctrl.GetEnabled() || inArray(a.GetId(), exclude) {
+			continue
+		}
+		if applianceHasFunction(a, functions) {
+			appliances = append(appliances, a)
+		}
+	}
+	sort.SliceStable(appliances, func(i, j int) bool {
+		return appliances[i].GetName() < appliances[j].GetName()
+	})
+	return appliances, nil
+}
+
+func resourceAppgateSiteUpgradeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	siteID := d.Get("site_id").(string)
+	log.Printf("[DEBUG] Creating appgatesdp_site_upgrade: %s", siteID)
+	start := time.Now()
+	functions, _ := readArrayOfStringsFromConfig(d.Get("functions").(*schema.Set).List())
+	functions = siteUpgradeFunctionsOrDefault(functions)
+	exclude, _ := readArrayOfStringsFromConfig(d.Get("exclude").(*schema.Set).List())
+	appliances, err := siteUpgradeAppliances(ctx, meta, siteID, functions, exclude)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(appliances) == 0 {
+		return diag.Errorf("No appliances to upgrade in site %s", siteID)
+	}
+	d.SetId(siteID)
+
+	var (
+		imageURL        = d.Get("image_url").(string)
+		version         = d.Get("version").(string)
+		switchPartition = d.Get("switch_partition").(bool)
+		batchSize       = d.Get("batch_size").(int)
+		upgraded        = make([]string, 0, len(appliances))
+		skipped         = make([]string, 0)
+	)
+	for i := 0; i < len(appliances); i += batchSize {
+		end := i + batchSize
+		if end > len(appliances) {
+			end = len(appliances)
+		}
+		batch := appliances[i:end]
+		timeout := d.Timeout(schema.TimeoutCreate) - time.Since(start)
+		skips := make([]bool, len(batch))
+		errs := make([]error, len(batch))
+		var wg sync.WaitGroup
+		for j := range batch {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				log.Printf("[DEBUG] Upgrading appliance %s in site %s", batch[j].GetName(), siteID)
+				skips[j], errs[j] = upgradeAppliance(ctx, meta, &batch[j], imageURL, version, switchPartition, timeout)
+			}(j)
+		}
+		wg.Wait()
+
+		failed := make([]string, 0)
+		for j, a := range batch {
+			switch {
+			case errs[j] != nil:
+				failed = append(failed, errs[j].Error())
+			case skips[j]:
+				skipped = append(skipped, a.GetId())
+			default:
+				upgraded = append(upgraded, a.GetId())
+			}
+		}
+		d.Set("upgraded", upgraded)
+		d.Set("skipped", skipped)
+		if len(failed) > 0 {
+			// stop on the first failed batch, the remaining appliances in the site are left as they are.
+			d.Set("status", UpgradeStatusFailed)
+			return diag.Errorf(
+				"Site upgrade stopped after %d of %d appliances:\n%s",
+				len(upgraded)+len(skipped), len(appliances), strings.Join(failed, "\n"),
+			)
+		}
+	}
+	d.Set("status", UpgradeStatusSuccess)
+	return resourceAppgateSiteUpgradeRead(ctx, d, meta)
+}
+
+func resourceAppgateSiteUpgradeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading appgatesdp_site_upgrade: %s", d.Id())
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.SitesApi
+	_, res, err := api.SitesIdGet(context.WithValue(ctx, openapi.ContextAccessToken, token), d.Id()).Execute()
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("Failed to read Site, %w", prettyPrintAPIError(err)))
+	}
+	d.Set("site_id", d.Id())
+	return nil
+}
+
+func resourceAppgateSiteUpgradeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// An installed upgrade can not be rolled back, the upgrade is only removed from the state.
+	log.Printf("[DEBUG] Deleting appgatesdp_site_upgrade: %s", d.Id())
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,95 @@
+package appgate
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestSiteUpgradeFunctionsOrDefault(t *testing.T) {
+	tests := []struct {
+		functions []string
+		want      []string
+	}{
+		{nil, []string{applianceFunctionGateway, applianceFunctionLogServer}},
+		{[]string{}, []string{applianceFunctionGateway, applianceFunctionLogServer}},
+		{[]string{applianceFunctionPortal}, []string{applianceFunctionPortal}},
+	}
+	for _, tt := range tests {
+		if got := siteUpgradeFunctionsOrDefault(tt.functions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("siteUpgradeFunctionsOrDefault(%v) = %v, want %v", tt.functions, got, tt.want)
+		}
+	}
+}
+
+// TestAccSiteUpgrade upgrades the gateways in the site APPGATE_UPGRADE_SITE_ID with the image APPGATE_UPGRADE_IMAGE_URL.
+func TestAccSiteUpgrade(t *testing.T) {
+	siteID, imageURL := os.Getenv("APPGATE_UPGRADE_SITE_ID"), os.Getenv("APPGATE_UPGRADE_IMAGE_URL")
+	if siteID == "" || imageURL == "" {
+		t.Skip("APPGATE_UPGRADE_SITE_ID and APPGATE_UPGRADE_IMAGE_URL must be set for the site upgrade test")
+	}
+	resourceName := "appgatesdp_site_upgrade.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteUpgrade(siteID, imageURL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "site_id", siteID),
+					resource.TestCheckResourceAttr(resourceName, "status", UpgradeStatusSuccess),
+					resource.TestCheckResourceAttr(resourceName, "functions.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "upgraded.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSiteUpgrade(siteID, imageURL string) string {
+	return fmt.Sprintf(`
+resource "appgatesdp_site_upgrade" "test" {
+  site_id    = "%s"
+  image_url  = "%s"
+  functions  = ["gateway"]
+  batch_size = 2
+}
+`, siteID, imageURL)
+}
+
+// TestAccSiteUpgradeDefaultFunctions upgrades the gateways and log servers in the site APPGATE_UPGRADE_SITE_ID
+// with the image APPGATE_UPGRADE_IMAGE_URL, without functions set.
+func TestAccSiteUpgradeDefaultFunctions(t *testing.T) {
+	siteID, imageURL := os.Getenv("APPGATE_UPGRADE_SITE_ID"), os.Getenv("APPGATE_UPGRADE_IMAGE_URL")
+	if siteID == "" || imageURL == "" {
+		t.Skip("APPGATE_UPGRADE_SITE_ID and APPGATE_UPGRADE_IMAGE_URL must be set for the site upgrade test")
+	}
+	resourceName := "appgatesdp_site_upgrade.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteUpgradeDefaultFunctions(siteID, imageURL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "site_id", siteID),
+					resource.TestCheckResourceAttr(resourceName, "status", UpgradeStatusSuccess),
+					resource.TestCheckResourceAttr(resourceName, "functions.#", "0"),
+					resource.TestCheckResourceAttrSet(resourceName, "upgraded.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSiteUpgradeDefaultFunctions(siteID, imageURL string) string {
+	return fmt.Sprintf(`
+resource "appgatesdp_site_upgrade" "test" {
+  site_id   = "%s"
+  image_url = "%s"
+}
+`, siteID, imageURL)
+}
@@ -0,0 +1,165 @@
+package appgate
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	tokenTypeAdministration = "administration"
+	tokenTypeAdminClaims    = "adminclaims"
+	tokenTypeEntitlements   = "entitlements"
+	tokenTypeClaims         = "claims"
+)
+
+// distinguishedNameRegex matches the distinguished name of a user, or of a device of the user.
+var distinguishedNameRegex = regexp.MustCompile(`(?i)^(CN=[^,]+,)?CN=[^,]+,OU=[^,]+$`)
+
+func tokenTypes() []string {
+	return []string{
+		tokenTypeAdministration,
+		tokenTypeAdminClaims,
+		tokenTypeEntitlements,
+		tokenTypeClaims,
+	}
+}
+
+// resourceAppgateTokenRevocation revokes the tokens of a user or device, or all tokens of a type, when it is created.
+// Unlike appgatesdp_blacklist_user the user can sign in again, and gets new tokens.
+func resourceAppgateTokenRevocation() *schema.Resource {
+	return oneShotActionResource(&schema.Resource{
+		CreateContext: resourceAppgateTokenRevocationCreate,
+
+		Schema: map[string]*schema.Schema{
+			"distinguished_name": {
+				Type:         schema.TypeString,
+				Description:  "Distinguished name of the user, CN=<username>,OU=<provider name>, or of the device, CN=<device ID>,CN=<username>,OU=<provider name>, to revoke the tokens of.",
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"distinguished_name", "token_type"},
+				ValidateFunc: validation.StringMatch(distinguishedNameRegex, "must be CN=<username>,OU=<provider name> or CN=<device ID>,CN=<username>,OU=<provider name>"),
+			},
+			"token_type": {
+				Type:         schema.TypeString,
+				Description:  "Only revoke tokens of this type. Without distinguished_name, the tokens of this type are revoked for all users.",
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(tokenTypes(), false),
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Description: "Reason for the revocation, shown in the audit log.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"delay_minutes": {
+				Type:          schema.TypeInt,
+				Description:   "Delay the revocation of the tokens of token_type for all users, to spread the renewal of the tokens over time.",
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"distinguished_name"},
+				ValidateFunc:  validation.IntAtLeast(0),
+			},
+			"tokens_per_second": {
+				Type:          schema.TypeFloat,
+				Description:   "Rate at which the tokens of token_type for all users are revoked.",
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"distinguished_name"},
+			},
+			"revoked_at": {
+				Type:        schema.TypeString,
+				Description: "Time of the revocation.",
+				Computed:    true,
+			},
+			"revoked_tokens": {
+				Type:        schema.TypeList,
+				Description: "Tokens revoked.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"distinguished_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"token_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issued": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expires": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}, "revoke the tokens again")
+}
+
+func resourceAppgateTokenRevocationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	token, err := meta.(*Client).GetToken()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	api := meta.(*Client).API.ActiveDevicesApi
+	ctx = context.WithValue(ctx, openapi.ContextAccessToken, token)
+
+	args := openapi.NewTokenRevocationRequestWithDefaults()
+	if v, ok := d.GetOk("token_type"); ok {
+		args.SetTokenType(v.(string))
+	}
+	if v, ok := d.GetOk("reason"); ok {
+		args.SetRevocationReason(v.(string))
+	}
+	if v, ok := d.GetOk("delay_minutes"); ok {
+		args.SetDelayMinutes(int32(v.(int)))
+	}
+	if v, ok := d.GetOk("tokens_per_second"); ok {
+		args.SetTokensPerSecond(float32(v.(float64)))
+	}
+
+	var result *openapi.TokenRevocationResponse
+	if dn, ok := d.GetOk("distinguished_name"); ok {
+		log.Printf("[DEBUG] Revoking %s tokens of %s", d.Get("token_type"), dn)
+		result, _, err = api.TokenRecordsRevokedByDnDistinguishedNamePut(ctx, dn.(string)).TokenRevocationRequest(*args).Execute()
+	} else {
+		tokenType := d.Get("token_type").(string)
+		log.Printf("[DEBUG] Revoking %s tokens of all users", tokenType)
+		result, _, err = api.TokenRecordsRevokedByTypeTokenTypePut(ctx, tokenType).TokenRevocationRequest(*args).Execute()
+	}
+	if err != nil {
+		return diag.Errorf("Could not revoke tokens, %s", prettyPrintAPIError(err))
+	}
+
+	revoked := make([]interface{}, 0, len(result.GetData()))
+	for _, r := range result.GetData() {
+		revoked = append(revoked, map[string]interface{}{
+			"id":                 r.GetId(),
+			"distinguished_name": r.GetDistinguishedName(),
+			"token_type":         r.GetTokenType(),
+			"issued":             r.GetIssued().UTC().Format(time.RFC3339),
+			"expires":            r.GetExpires().UTC().Format(time.RFC3339),
+		})
+	}
+	setOneShotActionID(d, "revoked_at")
+	if err := d.Set("revoked_tokens", revoked); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
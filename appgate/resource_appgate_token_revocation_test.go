@@ -0,0 +1,98 @@
+package appgate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTokenRevocationByDistinguishedName(t *testing.T) {
+	resourceName := "appgatesdp_token_revocation.incident"
+	rName := RandStringFromCharSet(10, CharSetAlphaNum)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTokenRevocationConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "distinguished_name", fmt.Sprintf("CN=%s,OU=local", rName)),
+					resource.TestCheckResourceAttr(resourceName, "token_type", "entitlements"),
+					resource.TestCheckResourceAttr(resourceName, "reason", "incident response"),
+					resource.TestCheckResourceAttrSet(resourceName, "revoked_at"),
+					// the user has never signed in, so there is nothing to revoke.
+					resource.TestCheckResourceAttr(resourceName, "revoked_tokens.#", "0"),
+					resource.TestCheckResourceAttr("data.appgatesdp_token_records.user", "distinguished_names.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTokenRevocationConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "appgatesdp_token_revocation" "incident" {
+  distinguished_name = "CN=%[1]s,OU=local"
+  token_type         = "entitlements"
+  reason             = "incident response"
+}
+
+data "appgatesdp_token_records" "user" {
+  distinguished_name = "CN=%[1]s,OU=local"
+}
+`, rName)
+}
+
+func TestDistinguishedNameRegex(t *testing.T) {
+	tests := map[string]bool{
+		"CN=alice,OU=local":                     true,
+		"cn=alice,ou=local":                     true,
+		"CN=4c07bc67,CN=alice,OU=local":         true,
+		"CN=4c07bc67,CN=alice,OU=local,OU=more": false,
+		"alice":                                 false,
+		"OU=local":                              false,
+	}
+	for dn, want := range tests {
+		if got := distinguishedNameRegex.MatchString(dn); got != want {
+			t.Errorf("distinguishedNameRegex.MatchString(%q) = %t, want %t", dn, got, want)
+		}
+	}
+}
+
+func TestDistinguishedNameMatches(t *testing.T) {
+	tests := []struct {
+		dn, filter string
+		want       bool
+	}{
+		{"CN=4c07bc67,CN=alice,OU=local", "CN=4c07bc67,CN=alice,OU=local", true},
+		{"CN=4c07bc67,CN=alice,OU=local", "CN=alice,OU=local", true},
+		{"CN=4c07bc67,CN=alice,OU=local", "cn=alice,ou=local", true},
+		{"CN=4c07bc67,CN=malice,OU=local", "CN=alice,OU=local", false},
+		{"CN=4c07bc67,CN=alice,OU=local", "CN=bob,OU=local", false},
+	}
+	for _, tt := range tests {
+		if got := distinguishedNameMatches(tt.dn, tt.filter); got != tt.want {
+			t.Errorf("distinguishedNameMatches(%q, %q) = %t, want %t", tt.dn, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestDistinguishedNameFilter(t *testing.T) {
+	tests := []struct {
+		filter distinguishedNameFilter
+		want   bool
+	}{
+		{distinguishedNameFilter{}, true},
+		{distinguishedNameFilter{distinguishedName: "CN=alice,OU=local"}, true},
+		{distinguishedNameFilter{distinguishedName: "CN=bob,OU=local"}, false},
+		{distinguishedNameFilter{username: "alice", providerName: "local"}, true},
+		{distinguishedNameFilter{username: "alice", providerName: "ldap"}, false},
+		{distinguishedNameFilter{username: "bob"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches("CN=4c07bc67,CN=alice,OU=local", "alice", "local"); got != tt.want {
+			t.Errorf("%+v.matches() = %t, want %t", tt.filter, got, tt.want)
+		}
+	}
+}
@@ -89,6 +89,7 @@ func resourceAppgateTrustedCertificateRead(d *schema.ResourceData, meta interfac
 		}
 		return fmt.Errorf("Failed to read trusted certificate, %w", err)
 	}
+	setUpdated(d, trustedCertificate.GetUpdated())
 	d.SetId(trustedCertificate.GetId())
 	d.Set("trusted_certificate_id", trustedCertificate.GetId())
 	d.Set("name", trustedCertificate.GetName())
@@ -112,6 +113,9 @@ func resourceAppgateTrustedCertificateUpdate(d *schema.ResourceData, meta interf
 	if err != nil {
 		return fmt.Errorf("Failed to read trusted certificate while updating, %w", err)
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateTrustedCertificate(), originalTrustedCertificate.GetUpdated()); err != nil {
+		return err
+	}
 
 	if d.HasChange("name") {
 		originalTrustedCertificate.SetName(d.Get("name").(string))
@@ -97,6 +97,7 @@ func resourceAppgateUserClaimScriptRead(ctx context.Context, d *schema.ResourceD
 		}
 		return diag.FromErr(fmt.Errorf("Failed to read User claim script, %w", err))
 	}
+	setUpdated(d, UserClaimScript.GetUpdated())
 	d.SetId(UserClaimScript.GetId())
 	d.Set("user_claim_script_id", UserClaimScript.GetId())
 	d.Set("name", UserClaimScript.GetName())
@@ -121,6 +122,9 @@ func resourceAppgateUserClaimScriptUpdate(ctx context.Context, d *schema.Resourc
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("Failed to read User Claim Script while updating, %w", err))
 	}
+	if err := checkConcurrentUpdate(ctx, d, meta, resourceAppgateUserClaimScript(), originalUserClaimScript.GetUpdated()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	if d.HasChange("name") {
 		originalUserClaimScript.SetName(d.Get("name").(string))
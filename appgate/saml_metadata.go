@@ -0,0 +1,187 @@
+package appgate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	samlBindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlBindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	// samlCertificateExpiryWarning is how long before the signing certificate expires that we warn.
+	samlCertificateExpiryWarning = 30 * 24 * time.Hour
+)
+
+type samlEntitiesDescriptor struct {
+	EntityDescriptors []samlEntityDescriptor `xml:"EntityDescriptor"`
+}
+
+type samlEntityDescriptor struct {
+	EntityID          string                 `xml:"entityID,attr"`
+	IDPSSODescriptors []samlIDPSSODescriptor `xml:"IDPSSODescriptor"`
+}
+
+type samlIDPSSODescriptor struct {
+	KeyDescriptors      []samlKeyDescriptor `xml:"KeyDescriptor"`
+	SingleSignOnService []samlEndpoint      `xml:"SingleSignOnService"`
+}
+
+type samlKeyDescriptor struct {
+	Use              string   `xml:"use,attr"`
+	X509Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// samlMetadata is the SAML identity provider settings parsed from IdP metadata.
+type samlMetadata struct {
+	Issuer      string
+	RedirectURL string
+	// Certificate is the active signing certificate in PEM format.
+	Certificate string
+	NotAfter    time.Time
+}
+
+// parseSAMLMetadata parses IdP metadata, such as the federation metadata published by Azure AD or Okta.
+// The active signing certificate is the first signing certificate in the metadata that is valid at now,
+// or the first signing certificate if none of them are valid.
+func parseSAMLMetadata(data []byte, now time.Time) (*samlMetadata, error) {
+	entity, err := decodeSAMLEntity(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(entity.IDPSSODescriptors) == 0 {
+		return nil, errors.New("SAML metadata does not contain an IDPSSODescriptor")
+	}
+	idp := entity.IDPSSODescriptors[0]
+	md := &samlMetadata{Issuer: entity.EntityID}
+	if len(md.Issuer) == 0 {
+		return nil, errors.New("SAML metadata does not contain an entityID")
+	}
+	for _, binding := range []string{samlBindingHTTPRedirect, samlBindingHTTPPost} {
+		for _, sso := range idp.SingleSignOnService {
+			if sso.Binding == binding && len(md.RedirectURL) == 0 {
+				md.RedirectURL = sso.Location
+			}
+		}
+	}
+	if len(md.RedirectURL) == 0 {
+		return nil, errors.New("SAML metadata does not contain a SingleSignOnService with HTTP-Redirect or HTTP-POST binding")
+	}
+
+	var certificates []*x509.Certificate
+	for _, key := range idp.KeyDescriptors {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, c := range key.X509Certificates {
+			der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(c), ""))
+			if err != nil {
+				return nil, fmt.Errorf("invalid X509Certificate in SAML metadata: %w", err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid X509Certificate in SAML metadata: %w", err)
+			}
+			certificates = append(certificates, cert)
+		}
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("SAML metadata does not contain a signing certificate")
+	}
+	active := certificates[0]
+	for _, cert := range certificates {
+		if !now.Before(cert.NotBefore) && !now.After(cert.NotAfter) {
+			active = cert
+			break
+		}
+	}
+	md.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: active.Raw}))
+	md.NotAfter = active.NotAfter
+	return md, nil
+}
+
+func decodeSAMLEntity(data []byte) (*samlEntityDescriptor, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid SAML metadata: %w", err)
+	}
+	switch root.XMLName.Local {
+	case "EntityDescriptor":
+		var entity samlEntityDescriptor
+		if err := xml.Unmarshal(data, &entity); err != nil {
+			return nil, fmt.Errorf("invalid SAML metadata: %w", err)
+		}
+		return &entity, nil
+	case "EntitiesDescriptor":
+		var entities samlEntitiesDescriptor
+		if err := xml.Unmarshal(data, &entities); err != nil {
+			return nil, fmt.Errorf("invalid SAML metadata: %w", err)
+		}
+		for _, e := range entities.EntityDescriptors {
+			if len(e.IDPSSODescriptors) > 0 {
+				return &e, nil
+			}
+		}
+		return nil, errors.New("SAML metadata does not contain an identity provider")
+	}
+	return nil, fmt.Errorf("invalid SAML metadata, unexpected root element %s", root.XMLName.Local)
+}
+
+// samePEMCertificate returns true if a and b contain the same certificate, regardless of formatting.
+func samePEMCertificate(a, b string) bool {
+	blockA, _ := pem.Decode([]byte(strings.TrimSpace(a)))
+	blockB, _ := pem.Decode([]byte(strings.TrimSpace(b)))
+	if blockA == nil || blockB == nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return bytes.Equal(blockA.Bytes, blockB.Bytes)
+}
+
+// validateSAMLMetadata validates metadata_xml, and warns if the active signing certificate
+// is expired or expires soon.
+func validateSAMLMetadata(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	now := time.Now()
+	md, err := parseSAMLMetadata([]byte(v.(string)), now)
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		})
+	}
+	switch {
+	case now.After(md.NotAfter):
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "SAML signing certificate is expired",
+			Detail:        fmt.Sprintf("The signing certificate in metadata_xml for %s expired %s.", md.Issuer, md.NotAfter.Format(time.RFC3339)),
+			AttributePath: path,
+		})
+	case md.NotAfter.Sub(now) < samlCertificateExpiryWarning:
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "SAML signing certificate expires soon",
+			Detail:        fmt.Sprintf("The signing certificate in metadata_xml for %s expires %s, update the metadata once the identity provider has rotated the certificate.", md.Issuer, md.NotAfter.Format(time.RFC3339)),
+			AttributePath: path,
+		})
+	}
+	return diags
+}
@@ -0,0 +1,147 @@
+package appgate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func testSAMLCertificate(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(notBefore.Unix()),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func testSAMLMetadata(certificates ...[]byte) string {
+	var keys strings.Builder
+	for _, c := range certificates {
+		fmt.Fprintf(&keys, `
+    <KeyDescriptor use="signing">
+      <KeyInfo xmlns="http://www.w3.org/2000/09/xmldsig#">
+        <X509Data>
+          <X509Certificate>%s</X509Certificate>
+        </X509Data>
+      </KeyInfo>
+    </KeyDescriptor>`, base64.StdEncoding.EncodeToString(c))
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sts.example.com/tenant/">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">%s
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://login.example.com/post"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://login.example.com/redirect"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`, keys.String())
+}
+
+func TestParseSAMLMetadata(t *testing.T) {
+	now := time.Now()
+	expired := testSAMLCertificate(t, now.Add(-2*365*24*time.Hour), now.Add(-24*time.Hour))
+	active := testSAMLCertificate(t, now.Add(-24*time.Hour), now.Add(365*24*time.Hour))
+	next := testSAMLCertificate(t, now.Add(24*time.Hour), now.Add(2*365*24*time.Hour))
+
+	md, err := parseSAMLMetadata([]byte(testSAMLMetadata(expired, active, next)), now)
+	if err != nil {
+		t.Fatalf("parseSAMLMetadata() error = %v", err)
+	}
+	if md.Issuer != "https://sts.example.com/tenant/" {
+		t.Errorf("Issuer = %q", md.Issuer)
+	}
+	if md.RedirectURL != "https://login.example.com/redirect" {
+		t.Errorf("RedirectURL = %q, want the HTTP-Redirect binding", md.RedirectURL)
+	}
+	want := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: active}))
+	if md.Certificate != want {
+		t.Errorf("Certificate is not the active signing certificate")
+	}
+
+	// wrapped in EntitiesDescriptor, with only a certificate that is not valid yet.
+	wrapped := `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">` +
+		strings.TrimPrefix(testSAMLMetadata(next), `<?xml version="1.0" encoding="utf-8"?>`) +
+		`</EntitiesDescriptor>`
+	md, err = parseSAMLMetadata([]byte(wrapped), now)
+	if err != nil {
+		t.Fatalf("parseSAMLMetadata() error = %v", err)
+	}
+	if !samePEMCertificate(md.Certificate, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: next}))) {
+		t.Errorf("Certificate should fall back to the first signing certificate")
+	}
+}
+
+func TestParseSAMLMetadataErrors(t *testing.T) {
+	tests := map[string]string{
+		"not xml":        "not xml",
+		"no idp":         `<EntityDescriptor entityID="x"></EntityDescriptor>`,
+		"no certificate": `<EntityDescriptor entityID="x"><IDPSSODescriptor><SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://x"/></IDPSSODescriptor></EntityDescriptor>`,
+		"no sso":         testSAMLMetadata()[:strings.Index(testSAMLMetadata(), "<SingleSignOnService")] + "</IDPSSODescriptor></EntityDescriptor>",
+	}
+	for name, metadata := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseSAMLMetadata([]byte(metadata), time.Now()); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestValidateSAMLMetadata(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		notAfter time.Time
+		severity diag.Severity
+		count    int
+	}{
+		"valid":         {notAfter: now.Add(365 * 24 * time.Hour), count: 0},
+		"expiring soon": {notAfter: now.Add(7 * 24 * time.Hour), severity: diag.Warning, count: 1},
+		"expired":       {notAfter: now.Add(-time.Hour), severity: diag.Warning, count: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cert := testSAMLCertificate(t, now.Add(-365*24*time.Hour), tt.notAfter)
+			diags := validateSAMLMetadata(testSAMLMetadata(cert), cty.GetAttrPath("metadata_xml"))
+			if len(diags) != tt.count {
+				t.Fatalf("validateSAMLMetadata() = %v, want %d diagnostics", diags, tt.count)
+			}
+			if tt.count > 0 && diags[0].Severity != tt.severity {
+				t.Errorf("validateSAMLMetadata() severity = %v, want %v", diags[0].Severity, tt.severity)
+			}
+		})
+	}
+}
+
+func TestSamePEMCertificate(t *testing.T) {
+	der := testSAMLCertificate(t, time.Now(), time.Now().Add(time.Hour))
+	a := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	b := strings.ReplaceAll(a, "\n", "\r\n") + "\n"
+	if !samePEMCertificate(a, b) {
+		t.Errorf("samePEMCertificate() = false for the same certificate with different line endings")
+	}
+	other := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testSAMLCertificate(t, time.Now(), time.Now().Add(time.Hour))}))
+	if samePEMCertificate(a, other) {
+		t.Errorf("samePEMCertificate() = true for different certificates")
+	}
+}
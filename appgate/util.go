@@ -13,6 +13,8 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/appgate/sdp-api-client-go/api/v22/openapi"
 	"github.com/appgate/terraform-provider-appgatesdp/appgate/hashcode"
@@ -62,6 +64,7 @@ func baseEntitySchema() map[string]*schema.Schema {
 			Default:     DefaultDescription,
 			Optional:    true,
 		},
+		"updated": updatedSchema(),
 	}
 	return mergeSchemaMaps(s, baseTagsSchema())
 }
@@ -280,6 +283,21 @@ func validateIPaddress(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
+// validateDuration validate a duration string, such as 10m.
+func validateDuration(v interface{}, name string) (warns []string, errs []error) {
+	s, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %q to be string", name))
+		return
+	}
+
+	if _, err := time.ParseDuration(s); err != nil {
+		errs = append(errs, fmt.Errorf("expected %q to be a valid duration, got %v", name, v))
+	}
+
+	return warns, errs
+}
+
 func inArray(needle string, haystack []string) bool {
 	sort.Strings(haystack)
 	i := sort.Search(len(haystack),
@@ -444,3 +462,111 @@ func FileExists(name string) (bool, error) {
 	}
 	return false, err
 }
+
+// mutexKV is a key/value store of mutexes, used to serialize read-modify-write
+// operations from different resources that updates the same object.
+type mutexKV struct {
+	lock  sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
+
+// Lock the mutex for the given key, creating it if needed.
+func (m *mutexKV) Lock(key string) {
+	log.Printf("[DEBUG] Locking %q", key)
+	m.get(key).Lock()
+	log.Printf("[DEBUG] Locked %q", key)
+}
+
+// Unlock the mutex for the given key.
+func (m *mutexKV) Unlock(key string) {
+	log.Printf("[DEBUG] Unlocking %q", key)
+	m.get(key).Unlock()
+	log.Printf("[DEBUG] Unlocked %q", key)
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
+
+// resourceLocks is keyed by object ID and shared by all resources in the provider.
+var resourceLocks = newMutexKV()
+
+// errConcurrentUpdate is returned by readModifyWrite when a write is overwritten by another writer.
+var errConcurrentUpdate = errors.New("the update was overwritten by a concurrent update")
+
+// readModifyWrite applies modify to the latest version of an object on the Controller and writes it back.
+// modify returns false if the object already has the modification, and then nothing is written.
+// The API has no conditional write, so the object is read again after the write, and if applied reports
+// that the modification is missing, another writer has overwritten it and the read-modify-write is retried.
+// Each writer that uses readModifyWrite verifies its own modification the same way, so concurrent writers
+// do not lose each others changes. Writers within the provider are also serialized by resourceLocks on key.
+func readModifyWrite[T any](ctx context.Context, key string, timeout time.Duration, read func() (T, error), modify func(T) (T, bool, error), write func(T) error, applied func(T) bool) error {
+	resourceLocks.Lock(key)
+	defer resourceLocks.Unlock(key)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = timeout
+	return backoff.Retry(func() error {
+		current, err := read()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		next, changed, err := modify(current)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		if !changed {
+			return nil
+		}
+		if err := write(next); err != nil {
+			return backoff.Permanent(err)
+		}
+		written, err := read()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		if !applied(written) {
+			log.Printf("[DEBUG] %s was overwritten by a concurrent update, retrying", key)
+			return errConcurrentUpdate
+		}
+		return nil
+	}, backoff.WithContext(b, ctx))
+}
+
+// listPageSize is the number of objects requested per page by listAllPages.
+const listPageSize = 100
+
+// listAllPages calls list with the range of each page, e.g. 0-99, until a page is not full,
+// and returns the objects of all pages. The end of the range is inclusive.
+func listAllPages[T any](list func(pageRange string) ([]T, error)) ([]T, error) {
+	all := make([]T, 0)
+	for start := 0; ; start += listPageSize {
+		page, err := list(fmt.Sprintf("%d-%d", start, start+listPageSize-1))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// isUUID returns true if s is an ID, and not a name.
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
@@ -0,0 +1,112 @@
+package appgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReadModifyWrite(t *testing.T) {
+	add := func(v string) func([]string) ([]string, bool, error) {
+		return func(list []string) ([]string, bool, error) {
+			for _, s := range list {
+				if s == v {
+					return list, false, nil
+				}
+			}
+			return append(append([]string{}, list...), v), true, nil
+		}
+	}
+	contains := func(v string) func([]string) bool {
+		return func(list []string) bool {
+			for _, s := range list {
+				if s == v {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	t.Run("retries a lost update", func(t *testing.T) {
+		stored := []string{"a"}
+		writes := 0
+		read := func() ([]string, error) { return stored, nil }
+		write := func(list []string) error {
+			writes++
+			stored = list
+			if writes == 1 {
+				// another writer overwrites the first write with the version it read before.
+				stored = []string{"a", "c"}
+			}
+			return nil
+		}
+		if err := readModifyWrite(context.Background(), "lost", time.Minute, read, add("b"), write, contains("b")); err != nil {
+			t.Fatalf("readModifyWrite() = %v", err)
+		}
+		if writes != 2 || !contains("b")(stored) || !contains("c")(stored) {
+			t.Fatalf("got %v after %d writes, want [a c b] after 2 writes", stored, writes)
+		}
+	})
+
+	t.Run("does not write without changes", func(t *testing.T) {
+		read := func() ([]string, error) { return []string{"a", "b"}, nil }
+		write := func(list []string) error {
+			t.Fatal("unexpected write")
+			return nil
+		}
+		if err := readModifyWrite(context.Background(), "unchanged", time.Minute, read, add("b"), write, contains("b")); err != nil {
+			t.Fatalf("readModifyWrite() = %v", err)
+		}
+	})
+
+	t.Run("stops on errors", func(t *testing.T) {
+		want := errors.New("not found")
+		read := func() ([]string, error) { return nil, want }
+		write := func(list []string) error { return nil }
+		if err := readModifyWrite(context.Background(), "error", time.Minute, read, add("b"), write, contains("b")); !errors.Is(err, want) {
+			t.Fatalf("readModifyWrite() = %v, want %v", err, want)
+		}
+	})
+}
+
+func TestListAllPages(t *testing.T) {
+	tests := []int{0, 1, listPageSize - 1, listPageSize, listPageSize + 1, 3 * listPageSize}
+	for _, n := range tests {
+		ranges := make([]string, 0)
+		got, err := listAllPages(func(pageRange string) ([]int, error) {
+			ranges = append(ranges, pageRange)
+			var start, end int
+			if _, err := fmt.Sscanf(pageRange, "%d-%d", &start, &end); err != nil {
+				return nil, err
+			}
+			page := make([]int, 0)
+			for i := start; i <= end && i < n; i++ {
+				page = append(page, i)
+			}
+			return page, nil
+		})
+		if err != nil {
+			t.Fatalf("listAllPages(%d) returned %s", n, err)
+		}
+		if len(got) != n {
+			t.Errorf("listAllPages(%d) returned %d objects", n, len(got))
+		}
+		for i, v := range got {
+			if v != i {
+				t.Errorf("listAllPages(%d)[%d] = %d", n, i, v)
+				break
+			}
+		}
+		if want := n/listPageSize + 1; len(ranges) != want {
+			t.Errorf("listAllPages(%d) requested %d pages %v, want %d", n, len(ranges), ranges, want)
+		}
+	}
+
+	wantErr := errors.New("list failed")
+	if _, err := listAllPages(func(string) ([]int, error) { return nil, wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("listAllPages returned %v, want %v", err, wantErr)
+	}
+}
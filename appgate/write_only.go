@@ -0,0 +1,62 @@
+package appgate
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// writeOnlySecretSchema returns the write-only secret <name>_wo, which conflicts with the secret stored in the
+// state at conflictsWith, and <name>_wo_version. The write-only value is never stored in the state, so it can come
+// from an ephemeral resource, and changing <name>_wo_version is what sends a new value to the controller.
+func writeOnlySecretSchema(name, conflictsWith, description string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		name + "_wo": {
+			Type:          schema.TypeString,
+			Description:   description + " Write-only, the value is not stored in the state. Requires Terraform 1.11 or later.",
+			Optional:      true,
+			Sensitive:     true,
+			WriteOnly:     true,
+			ConflictsWith: []string{conflictsWith},
+		},
+		name + "_wo_version": {
+			Type:         schema.TypeInt,
+			Description:  "Version of " + name + "_wo, change it to update the secret on the controller.",
+			Optional:     true,
+			RequiredWith: []string{name + "_wo"},
+		},
+	}
+}
+
+// preferWriteOnlySecret warns if name is used instead of <name>_wo, when Terraform supports write-only attributes.
+func preferWriteOnlySecret(name string) schema.ValidateRawResourceConfigFunc {
+	return validation.PreferWriteOnlyAttribute(cty.GetAttrPath(name), cty.GetAttrPath(name+"_wo"))
+}
+
+// secretFromConfig returns the secret from name, or from the write-only <name>_wo.
+func secretFromConfig(d *schema.ResourceData, name string) (string, bool) {
+	if v, ok := d.GetOk(name); ok {
+		return v.(string), true
+	}
+	return writeOnlyFromConfig(d, name+"_wo")
+}
+
+// writeOnlyFromConfig returns the write-only string attribute name, which is only available in the configuration.
+func writeOnlyFromConfig(d *schema.ResourceData, name string) (string, bool) {
+	return writeOnlyFromConfigAt(d, cty.GetAttrPath(name))
+}
+
+// writeOnlyFromConfigAt is writeOnlyFromConfig for a write-only attribute in a nested block.
+func writeOnlyFromConfigAt(d *schema.ResourceData, path cty.Path) (string, bool) {
+	v, diags := d.GetRawConfigAt(path)
+	if diags.HasError() || !v.Type().Equals(cty.String) || v.IsNull() || !v.IsKnown() {
+		return "", false
+	}
+	return v.AsString(), len(v.AsString()) > 0
+}
+
+// secretHasChange returns true if the secret in name, or the version of the write-only secret has changed.
+// The write-only value itself is always null in the plan, so it never has a change.
+func secretHasChange(d *schema.ResourceData, name string) bool {
+	return d.HasChanges(name, name+"_wo_version")
+}
@@ -6,7 +6,7 @@ import (
 	"flag"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 
 	"github.com/appgate/terraform-provider-appgatesdp/appgate"
 )
@@ -17,16 +17,16 @@ func main() {
 	flag.BoolVar(&debugMode, "debuggable", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	providerServer, err := appgate.ProviderServerFactory(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf5server.ServeOpt
 	if debugMode {
-		err := plugin.Debug(context.Background(), "registry.terraform.io/appgate/appgatesdp",
-			&plugin.ServeOpts{
-				ProviderFunc: appgate.Provider,
-			})
-		if err != nil {
-			log.Println(err.Error())
-		}
-	} else {
-		plugin.Serve(&plugin.ServeOpts{
-			ProviderFunc: appgate.Provider})
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+	if err := tf5server.Serve("registry.terraform.io/appgate/appgatesdp", providerServer, serveOpts...); err != nil {
+		log.Fatal(err)
 	}
 }